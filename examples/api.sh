@@ -8,4 +8,12 @@ curl --request GET \
   --url 'http://localhost:8080/transactions?page=1&pageSize=5&dateStart=2023-01-01&dateEnd=2024-01-31&sort=trx_date%20desc&trx_type=expense&account_number=001901007760509&trx_amount=72608'
 
 curl --request GET \
-  --url 'http://localhost:8080/transactions?page=1&pageSize=5&dateStart=2023-01-01&dateEnd=2024-01-31&sort=trx_date%20desc&trx_type=expense&account_number=001901007760509&trx_amount=72608&search=TX'
\ No newline at end of file
+  --url 'http://localhost:8080/transactions?page=1&pageSize=5&dateStart=2023-01-01&dateEnd=2024-01-31&sort=trx_date%20desc&trx_type=expense&account_number=001901007760509&trx_amount=72608&search=TX'
+curl --get \
+  --url 'http://localhost:8080/transactions?page=1&pageSize=5&sort=trx_date%20desc' \
+  --data-urlencode 'filter=trx_type==income;trx_amount=ge=100,(account_number=in=(123,456))'
+
+curl --request POST \
+  --url 'http://localhost:8080/transactions/search' \
+  --header 'Content-Type: application/json' \
+  --data '{"trx_amount": {"$gte": 100}, "$or": [{"trx_type": "income"}, {"cif": {"$like": "ABC%"}}], "$sort": ["trx_date desc"], "$pageSize": 5}'
@@ -1,12 +1,38 @@
 package transaction
 
 import (
+	"errors"
 	"net/http"
+
+	"github.com/xans-me/gorm-pagination/pagination"
 )
 
 // GetTransactions handles the request for paginated transactions.
 func GetTransactions(w http.ResponseWriter, r *http.Request) {
 	response, err := GetPaginatedTransactions(r)
+	respondWithResult(w, response, err)
+}
+
+// SearchTransactions handles the request for transactions matching a JSON search document.
+func SearchTransactions(w http.ResponseWriter, r *http.Request) {
+	response, err := SearchPaginatedTransactions(r)
+	respondWithResult(w, response, err)
+}
+
+// respondWithResult writes the paginated result, or the error with a matching status code.
+func respondWithResult(w http.ResponseWriter, response interface{}, err error) {
+	var (
+		validationErr *pagination.ValidationError
+		syntaxErr     *pagination.FilterSyntaxError
+	)
+	if errors.As(err, &validationErr) || errors.As(err, &syntaxErr) {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, pagination.ErrQueryTimeout) {
+		RespondWithError(w, http.StatusGatewayTimeout, err.Error())
+		return
+	}
 	if err != nil {
 		RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -6,4 +6,5 @@ import (
 
 func RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/transactions", GetTransactions).Methods("GET")
+	r.HandleFunc("/transactions/search", SearchTransactions).Methods("POST")
 }
@@ -3,8 +3,10 @@ package transaction
 import (
 	"github.com/xans-me/gorm-pagination/pagination"
 	"gorm.io/gorm"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 func GetPaginatedTransactions(r *http.Request) (interface{}, error) {
@@ -27,6 +29,13 @@ func GetPaginatedTransactions(r *http.Request) (interface{}, error) {
 	accountNumber := r.URL.Query().Get("account_number")
 	trxAmountStr := r.URL.Query().Get("trx_amount")
 	search := r.URL.Query().Get("search")
+	compare := r.URL.Query().Get("compare") // "previous" or "year"
+
+	// Parse the filter query, e.g. filter=trx_type==income;trx_amount=ge=100
+	filter, err := pagination.ParseFilter(r.URL.Query().Get("filter"))
+	if err != nil {
+		return nil, err
+	}
 
 	// Convert transaction amount to float64
 	var trxAmount float64
@@ -44,9 +53,6 @@ func GetPaginatedTransactions(r *http.Request) (interface{}, error) {
 	addDateRangeFilter(&filterManager, dateStart, dateEnd)
 	addTransactionAmountFilter(&filterManager, trxAmount)
 
-	// Apply filters from FilterManager
-	query = filterManager.Apply(query)
-
 	// Apply manual filters
 	query = applyManualFilters(query, accountNumber, search)
 
@@ -59,19 +65,22 @@ func GetPaginatedTransactions(r *http.Request) (interface{}, error) {
 		pagination.WithPage(page),
 		pagination.WithPageSize(pageSize),
 		pagination.WithSort(sort...),
+		pagination.WithModelFields(), // Reject sorts and filters on unknown columns
+		pagination.WithFilters(&filterManager, filter),
+		pagination.WithQueryTimeout(10*time.Second),
+		pagination.WithSummaryComparison(pagination.ComparePeriod(compare)),
 		// Adding various summary fields dynamically
 		pagination.WithSummaryFields(
 			"trx_amount:sum",
-			"trx_amount:min",                      // Min of trx_amount
-			"trx_amount:max",                      // Max of trx_amount// Sum of trx_amount
-			"account_number:distribution",         // Distribution of trx_type
-			"trx_type:value_count:income|expense", // Count of 'income' and 'expense'
-			"trx_type:value_count"),               // This will count all non-NULL trx_type records
+			"trx_amount:min", // Min of trx_amount
+			"trx_amount:max", // Max of trx_amount// Sum of trx_amount
+			"account_number:distribution:limit=10|by=trx_amount", // Top 10 accounts by amount
+			"trx_type:value_count:income|expense",                // Count of 'income' and 'expense'
+			"trx_type:value_count"),                              // This will count all non-NULL trx_type records
 	)
 
 	// Execute pagination and return results
-	var transactions []Data
-	result, err := paginator.Paginate(&transactions)
+	result, err := pagination.PaginateContext[Data](r.Context(), paginator)
 	if err != nil {
 		return nil, err
 	}
@@ -79,6 +88,27 @@ func GetPaginatedTransactions(r *http.Request) (interface{}, error) {
 	return result, nil
 }
 
+// SearchPaginatedTransactions paginates the transactions matching the JSON search document in
+// the request body, e.g. {"trx_amount": {"$gte": 100}, "$sort": ["trx_date desc"]}.
+func SearchPaginatedTransactions(r *http.Request) (interface{}, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	paginator := pagination.NewPaginator(
+		GetDB().Model(&Data{}),
+		pagination.WithModelFields(), // Reject searches and sorts on unknown columns
+		pagination.WithQueryTimeout(10*time.Second),
+		pagination.WithSummaryFields("trx_amount:sum", "trx_type:value_count:income|expense"),
+	)
+	if err := paginator.DecodeSearch(body, &Data{}); err != nil {
+		return nil, err
+	}
+
+	return pagination.PaginateContext[Data](r.Context(), paginator)
+}
+
 // addDateRangeFilter adds a date range filter to the FilterManager.
 func addDateRangeFilter(filterManager *pagination.FilterManager, dateStart, dateEnd string) {
 	if dateStart != "" && dateEnd != "" {
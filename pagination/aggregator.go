@@ -0,0 +1,225 @@
+package pagination
+
+import (
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Aggregator computes the value of a summary from the rows it aggregates. db is a query on
+// those rows, already filtered and bound to the request context; spec describes the summary,
+// including any arguments of the string form in spec.Args. spec.Field comes from the request,
+// so pass it to the SQL as a quoted clause.Column var rather than concatenating it.
+type Aggregator interface {
+	Aggregate(db *gorm.DB, spec SummarySpec) (interface{}, error)
+}
+
+// ScalarAggregator is an Aggregator that can also be expressed as a single aggregate SQL
+// expression. Scalars of all summaries share one query, and support subtotals (By) and
+// conditions (Where). A nil Scalar falls back to Aggregate, e.g. on an unsupported dialect.
+// Outside of Postgres, a scalar with conditions that it does not apply itself (Filtered) also
+// falls back to Aggregate, on a query restricted to the conditions, and cannot be subtotalled.
+type ScalarAggregator interface {
+	Aggregator
+	Scalar(db *gorm.DB, spec SummarySpec) (*Scalar, error)
+}
+
+// Scalar is an aggregate SQL expression over the summarized rows, such as "SUM(trx_amount)".
+type Scalar struct {
+	SQL       string
+	Vars      []interface{}
+	Count     bool                  // scanned as an int64 count instead of a float64
+	Transform func(float64) float64 // applied to the scanned value, if set
+	Filtered  bool                  // SQL already applies spec.Conditions itself
+}
+
+// AggregatorFunc adapts a function to the Aggregator interface.
+type AggregatorFunc func(db *gorm.DB, spec SummarySpec) (interface{}, error)
+
+// Aggregate calls f(db, spec).
+func (f AggregatorFunc) Aggregate(db *gorm.DB, spec SummarySpec) (interface{}, error) {
+	return f(db, spec)
+}
+
+// ScalarFunc adapts a function building a Scalar to the ScalarAggregator interface. Used on
+// its own, the scalar runs as a single query.
+type ScalarFunc func(db *gorm.DB, spec SummarySpec) (*Scalar, error)
+
+// Scalar calls f(db, spec).
+func (f ScalarFunc) Scalar(db *gorm.DB, spec SummarySpec) (*Scalar, error) {
+	return f(db, spec)
+}
+
+// Aggregate runs the scalar as a single query.
+func (f ScalarFunc) Aggregate(db *gorm.DB, spec SummarySpec) (interface{}, error) {
+	scalar, err := f(db, spec)
+	if err != nil {
+		return nil, err
+	}
+	values, err := scanScalars(db, []scalarAggregation{scalar.aggregation(spec.key())})
+	if err != nil {
+		return nil, err
+	}
+	return values[0], nil
+}
+
+// aggregation turns s into a column of the combined summary query.
+func (s *Scalar) aggregation(key string) scalarAggregation {
+	return scalarAggregation{key: key, sql: s.SQL, vars: s.Vars, count: s.Count, transform: s.Transform}
+}
+
+var (
+	aggregatorsMu sync.RWMutex
+	aggregators   = map[string]Aggregator{
+		"sum":            sqlFunction("SUM(", false),
+		"min":            sqlFunction("MIN(", false),
+		"max":            sqlFunction("MAX(", false),
+		"avg":            sqlFunction("AVG(", false),
+		"count":          sqlFunction("COUNT(", true), // Count non-NULL values
+		"count_distinct": sqlFunction("COUNT(DISTINCT ", true),
+		"variance":       ScalarFunc(varianceAggregator(false)),
+		"stddev":         ScalarFunc(varianceAggregator(true)),
+		"value_count":    ScalarFunc(valueCount),
+		"net":            ScalarFunc(net),
+		"distribution":   AggregatorFunc(distributionAggregator),
+		"date_histogram": AggregatorFunc(dateHistogramAggregator),
+		"range":          AggregatorFunc(numericHistogramAggregator),
+		"histogram":      AggregatorFunc(numericHistogramAggregator),
+	}
+)
+
+// RegisterAggregator makes an aggregator available as summary type name, both in the string
+// form ("field:name:args") and through Aggregate. Registering a name again replaces the
+// previous aggregator, including the built-in ones. It panics if aggregator is nil.
+func RegisterAggregator(name string, aggregator Aggregator) {
+	if aggregator == nil {
+		panic("pagination: RegisterAggregator aggregator is nil")
+	}
+
+	aggregatorsMu.Lock()
+	defer aggregatorsMu.Unlock()
+	aggregators[name] = aggregator
+}
+
+// lookUpAggregator returns the aggregator of a summary type. Percentile types such as "median"
+// or "p90" are not registered by name.
+func lookUpAggregator(name string) (Aggregator, bool) {
+	aggregatorsMu.RLock()
+	aggregator, ok := aggregators[name]
+	aggregatorsMu.RUnlock()
+	if ok {
+		return aggregator, true
+	}
+
+	if fraction, ok := percentileFraction(name); ok {
+		return percentileAggregator(fraction), true
+	}
+	return nil, false
+}
+
+// sqlFunction aggregates the field with a single SQL function call opened by call, e.g. "SUM(".
+func sqlFunction(call string, count bool) ScalarFunc {
+	return func(db *gorm.DB, spec SummarySpec) (*Scalar, error) {
+		field, filtered, err := aggregated(db, spec)
+		if err != nil {
+			return nil, err
+		}
+		return &Scalar{SQL: call + "?)", Vars: []interface{}{field}, Count: count, Filtered: filtered}, nil
+	}
+}
+
+// varianceAggregator builds the sample variance or standard deviation.
+func varianceAggregator(stddev bool) ScalarFunc {
+	return func(db *gorm.DB, spec SummarySpec) (*Scalar, error) {
+		field, filtered, err := aggregated(db, spec)
+		if err != nil {
+			return nil, err
+		}
+
+		scalar := varianceScalar(db.Dialector.Name(), field, stddev)
+		scalar.Filtered = filtered
+		return scalar, nil
+	}
+}
+
+// valueCount counts the rows holding spec.Value.
+func valueCount(db *gorm.DB, spec SummarySpec) (*Scalar, error) {
+	field, filtered, err := aggregated(db, spec)
+	if err != nil {
+		return nil, err
+	}
+	return &Scalar{SQL: "COUNT(CASE WHEN ? = ? THEN 1 END)", Vars: []interface{}{field, spec.Value}, Count: true, Filtered: filtered}, nil
+}
+
+// net subtracts the sum of the field over the outflow rows from the sum over the inflow rows.
+// The conditions of the spec restrict both sides.
+func net(db *gorm.DB, spec SummarySpec) (*Scalar, error) {
+	inflow, err := condition(db, append(append([]Filter(nil), spec.Conditions...), spec.Inflow...))
+	if err != nil {
+		return nil, err
+	}
+	outflow, err := condition(db, append(append([]Filter(nil), spec.Conditions...), spec.Outflow...))
+	if err != nil {
+		return nil, err
+	}
+
+	field := clause.Column{Name: spec.Field}
+	if db.Dialector.Name() == "postgres" {
+		sum := "COALESCE(SUM(?) FILTER (WHERE ?), 0)"
+		return &Scalar{SQL: sum + " - " + sum, Vars: []interface{}{field, inflow, field, outflow}, Filtered: true}, nil
+	}
+	sum := "COALESCE(SUM(CASE WHEN (?) THEN ? END), 0)"
+	return &Scalar{SQL: sum + " - " + sum, Vars: []interface{}{inflow, field, outflow, field}, Filtered: true}, nil
+}
+
+// percentileAggregator computes a continuous percentile: with PERCENTILE_CONT on Postgres and
+// interpolated in Go elsewhere, including its subtotals.
+type percentileAggregator float64
+
+func (f percentileAggregator) Scalar(db *gorm.DB, spec SummarySpec) (*Scalar, error) {
+	if db.Dialector.Name() != "postgres" {
+		return nil, nil
+	}
+	return &Scalar{SQL: "PERCENTILE_CONT(?) WITHIN GROUP (ORDER BY ?)", Vars: []interface{}{float64(f), clause.Column{Name: spec.Field}}}, nil
+}
+
+func (f percentileAggregator) Aggregate(db *gorm.DB, spec SummarySpec) (interface{}, error) {
+	if len(spec.GroupBy) > 0 {
+		return percentileSubtotals(db, spec, float64(f))
+	}
+
+	value, err := percentile(db, spec.Field, float64(f))
+	if err != nil {
+		return nil, err
+	}
+	return percentileValue(value, spec.decimal), nil
+}
+
+// distributionAggregator counts the rows per distinct value.
+func distributionAggregator(db *gorm.DB, spec SummarySpec) (interface{}, error) {
+	if err := spec.validateDistribution(); err != nil {
+		return nil, err
+	}
+	return distribution(db, spec)
+}
+
+// dateHistogramAggregator counts the rows per time bucket.
+func dateHistogramAggregator(db *gorm.DB, spec SummarySpec) (interface{}, error) {
+	if !validInterval(spec.Interval) {
+		return nil, fmt.Errorf("%w: unknown interval %q", ErrInvalidSummary, spec.Interval)
+	}
+	if spec.Location != nil && db.Dialector.Name() != "postgres" && !fixedOffset(spec.Location) {
+		return nil, fmt.Errorf("%w: time zone %q has daylight saving time, which only Postgres supports", ErrInvalidSummary, spec.Location)
+	}
+	return dateHistogram(db, spec)
+}
+
+// numericHistogramAggregator counts the rows per numeric range or equal-width bucket.
+func numericHistogramAggregator(db *gorm.DB, spec SummarySpec) (interface{}, error) {
+	if err := spec.validateBuckets(); err != nil {
+		return nil, err
+	}
+	return numericHistogram(db, spec)
+}
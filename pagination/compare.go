@@ -0,0 +1,263 @@
+package pagination
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ComparePeriod selects the period summaries are compared against.
+type ComparePeriod string
+
+const (
+	// ComparePrevious compares against the period of equal length right before the date range.
+	ComparePrevious ComparePeriod = "previous"
+	// CompareLastYear compares against the same date range one year earlier.
+	CompareLastYear ComparePeriod = "year"
+)
+
+// SummaryComparison holds the summaries of the compared period, keyed like Result.Summary.
+type SummaryComparison struct {
+	Period    ComparePeriod            `json:"period"`
+	StartDate string                   `json:"startDate"`
+	EndDate   string                   `json:"endDate"`
+	Values    map[string]ComparedValue `json:"values"`
+	Errors    map[string]string        `json:"errors,omitempty"`
+}
+
+// ComparedValue compares a summary value with its value in the compared period. Change and
+// PercentChange are only set for numeric values; PercentChange is also left out when the
+// previous value is zero.
+type ComparedValue struct {
+	Previous      interface{} `json:"previous"`
+	Change        interface{} `json:"change,omitempty"`
+	PercentChange *float64    `json:"percentChange,omitempty"`
+}
+
+// dateLayouts are the accepted formats of DateRangeFilter dates, with the smallest step that
+// separates two adjacent periods.
+var dateLayouts = []struct {
+	layout string
+	step   time.Duration
+}{
+	{"2006-01-02", 24 * time.Hour},
+	{"2006-01-02 15:04:05", time.Second},
+	{time.RFC3339, time.Second},
+}
+
+// comparedRange returns the date range filter matching the compared period of f. Both ends of
+// f are inclusive, so the previous period ends one step before f starts.
+func comparedRange(f DateRangeFilter, period ComparePeriod) (DateRangeFilter, error) {
+	for _, format := range dateLayouts {
+		start, err := time.Parse(format.layout, f.StartDate)
+		if err != nil {
+			continue
+		}
+		end, err := time.Parse(format.layout, f.EndDate)
+		if err != nil {
+			return DateRangeFilter{}, fmt.Errorf("%w: invalid end date %q", ErrInvalidSummary, f.EndDate)
+		}
+
+		switch period {
+		case ComparePrevious:
+			end, start = start.Add(-format.step), start.Add(-format.step-end.Sub(start))
+		case CompareLastYear:
+			start, end = start.AddDate(-1, 0, 0), end.AddDate(-1, 0, 0)
+		default:
+			return DateRangeFilter{}, fmt.Errorf("%w: unknown comparison period %q", ErrInvalidSummary, period)
+		}
+
+		f.StartDate, f.EndDate = start.Format(format.layout), end.Format(format.layout)
+		return f, nil
+	}
+	return DateRangeFilter{}, fmt.Errorf("%w: invalid start date %q", ErrInvalidSummary, f.StartDate)
+}
+
+// comparedBetween moves a BetweenFilter on dates, given as text or time.Time as DecodeSearch
+// produces them, to the compared period. The range is nil when f does not filter on dates.
+func comparedBetween(f BetweenFilter, period ComparePeriod) (BetweenFilter, *DateRangeFilter, error) {
+	// Times are formatted with the shortest layout that keeps both bounds, e.g. dates only
+	text := func(value interface{}, layout string) (string, bool) {
+		switch value := value.(type) {
+		case string:
+			_, err := time.Parse(layout, value)
+			return value, err == nil
+		case time.Time:
+			at, err := time.Parse(layout, value.Format(layout))
+			return value.Format(layout), err == nil && at.Equal(value)
+		}
+		return "", false
+	}
+	var start, end, layout string
+	for _, format := range dateLayouts {
+		low, okLow := text(f.Low, format.layout)
+		high, okHigh := text(f.High, format.layout)
+		if okLow && okHigh {
+			start, end, layout = low, high, format.layout
+			break
+		}
+	}
+	if layout == "" {
+		return f, nil, nil
+	}
+
+	r, err := comparedRange(DateRangeFilter{Field: f.Field, StartDate: start, EndDate: end}, period)
+	if err != nil {
+		return f, nil, err
+	}
+
+	// Keep the type of the bounds, so that the column compares with them as before
+	bound := func(original interface{}, value string) interface{} {
+		if _, ok := original.(time.Time); ok {
+			at, _ := time.Parse(layout, value)
+			return at
+		}
+		return value
+	}
+	f.Low, f.High = bound(f.Low, r.StartDate), bound(f.High, r.EndDate)
+	return f, &r, nil
+}
+
+// comparedFilters returns the paginator filters with the first DateRangeFilter, or BetweenFilter
+// on dates, possibly inside a FilterManager or an And group, moved to the compared period, and
+// that moved range. The range is nil when no date range is filtered on.
+func comparedFilters(filters []Filter, period ComparePeriod) ([]Filter, *DateRangeFilter, error) {
+	compared := append([]Filter(nil), filters...)
+	for i, filter := range compared {
+		var (
+			replaced Filter
+			rng      *DateRangeFilter
+			err      error
+		)
+		switch f := filter.(type) {
+		case DateRangeFilter:
+			var r DateRangeFilter
+			r, err = comparedRange(f, period)
+			replaced, rng = r, &r
+		case *DateRangeFilter:
+			var r DateRangeFilter
+			r, err = comparedRange(*f, period)
+			replaced, rng = &r, &r
+		case *FilterManager:
+			var and []Filter
+			if and, rng, err = comparedFilters(f.AndFilters, period); rng != nil {
+				replaced = &FilterManager{AndFilters: and, OrFilters: f.OrFilters}
+			}
+		case BetweenFilter:
+			var b BetweenFilter
+			if b, rng, err = comparedBetween(f, period); rng != nil {
+				replaced = b
+			}
+		case *BetweenFilter:
+			var b BetweenFilter
+			if b, rng, err = comparedBetween(*f, period); rng != nil {
+				replaced = &b
+			}
+		case AndFilter:
+			var and []Filter
+			if and, rng, err = comparedFilters(f.Filters, period); rng != nil {
+				replaced = AndFilter{Filters: and}
+			}
+		case *AndFilter:
+			var and []Filter
+			if and, rng, err = comparedFilters(f.Filters, period); rng != nil {
+				replaced = &AndFilter{Filters: and}
+			}
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+		if rng != nil {
+			compared[i] = replaced
+			return compared, rng, nil
+		}
+	}
+	return nil, nil, nil
+}
+
+// comparisonPhases prepares the summaries of the compared period: the same aggregations with
+// the date range filter moved. It returns no phases when no comparison is requested or there
+// is no date range filter to derive the period from.
+func (p *Paginator) comparisonPhases(model interface{}) (*SummaryComparison, *summaryResult, []phase, error) {
+	if p.Comparison == "" {
+		return nil, nil, nil, nil
+	}
+
+	filters, rng, err := comparedFilters(p.Filters, p.Comparison)
+	if err != nil || rng == nil {
+		return nil, nil, nil, err
+	}
+
+	summary, phases, err := p.summaryPhases(p.summarySpecs(SummaryScopeFiltered), func(ctx context.Context) *gorm.DB {
+		query := p.DB.WithContext(ctx)
+		for _, filter := range filters {
+			query = filter.Apply(query)
+		}
+		return query.Model(model)
+	})
+	if err != nil || summary == nil {
+		return nil, nil, nil, err
+	}
+
+	comparison := &SummaryComparison{Period: p.Comparison, StartDate: rng.StartDate, EndDate: rng.EndDate}
+	return comparison, summary, phases, nil
+}
+
+// compare fills the compared values of every summary value in current.
+func (c *SummaryComparison) compare(current map[string]interface{}, previous *summaryResult) {
+	values, errors := previous.result()
+	c.Values, c.Errors = make(map[string]ComparedValue, len(values)), errors
+	for key, value := range values {
+		c.Values[key] = compareValue(current[key], value)
+	}
+}
+
+// compareValue computes the change from previous to current for counts, float64 and Decimal
+// values. Other values, such as distributions, are only reported.
+func compareValue(current, previous interface{}) ComparedValue {
+	compared := ComparedValue{Previous: previous}
+	switch previous := previous.(type) {
+	case int64:
+		if current, ok := current.(int64); ok {
+			compared.Change = current - previous
+			compared.PercentChange = percentChange(float64(current), float64(previous))
+		}
+	case float64:
+		if current, ok := current.(float64); ok {
+			compared.Change = current - previous
+			compared.PercentChange = percentChange(current, previous)
+		}
+	case Decimal:
+		current, ok := current.(Decimal)
+		if !ok || !current.Valid || !previous.Valid {
+			break
+		}
+
+		x, errX := current.Float64()
+		y, errY := previous.Float64()
+		if errX != nil || errY != nil {
+			break
+		}
+
+		// Subtract exactly, keeping the larger number of decimals of both values
+		compared.Change = subtractDecimals(current, previous)
+		compared.PercentChange = percentChange(x, y)
+	}
+	return compared
+}
+
+// percentChange returns the change from previous to current as a percentage rounded to two
+// decimals, or nil when previous is zero.
+func percentChange(current, previous float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	percent := percentOf(current-previous, previous)
+	if previous < 0 {
+		percent = -percent
+	}
+	return &percent
+}
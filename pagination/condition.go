@@ -0,0 +1,66 @@
+package pagination
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Where restricts the aggregation to the rows matching every filter, e.g. the sum of the
+// incoming amounts only. Scalar aggregations compile the filters to FILTER (WHERE ...) on
+// Postgres and CASE WHEN ... on other databases, so they still share the combined query.
+func (s SummarySpec) Where(filters ...Filter) SummarySpec {
+	s.Conditions = append(append([]Filter(nil), s.Conditions...), filters...)
+	return s
+}
+
+// Net sums field over the rows matching inflow minus the sum over the rows matching outflow,
+// e.g. the net flow of incoming and outgoing transactions.
+func Net(field string, inflow, outflow Filter) SummarySpec {
+	return SummarySpec{Field: field, Type: "net", Inflow: []Filter{inflow}, Outflow: []Filter{outflow}}
+}
+
+// condition compiles filters, combined with AND, into one predicate. The predicate is kept as
+// an expression, so it binds its variables wherever it is embedded.
+func condition(db *gorm.DB, filters []Filter) (clause.Expression, error) {
+	return And(filters...).Expression(db)
+}
+
+// conditional restricts a scalar aggregation to the rows matching cond with a FILTER clause,
+// supported by Postgres only.
+func conditional(scalar scalarAggregation, cond clause.Expression) scalarAggregation {
+	scalar.sql += " FILTER (WHERE ?)"
+	scalar.vars = append(append([]interface{}(nil), scalar.vars...), cond)
+	return scalar
+}
+
+// aggregated returns the field aggregated by a built-in scalar. Without FILTER, outside of
+// Postgres, it is CASE WHEN cond THEN field END instead, which the aggregate functions skip as
+// NULL when the conditions of spec do not hold; filtered reports that case.
+func aggregated(db *gorm.DB, spec SummarySpec) (field clause.Expr, filtered bool, err error) {
+	column := clause.Column{Name: spec.Field}
+	if len(spec.Conditions) == 0 || db.Dialector.Name() == "postgres" {
+		return clause.Expr{SQL: "?", Vars: []interface{}{column}}, false, nil
+	}
+
+	cond, err := condition(db, spec.Conditions)
+	if err != nil {
+		return clause.Expr{}, false, err
+	}
+	return clause.Expr{SQL: "CASE WHEN (?) THEN ? END", Vars: []interface{}{cond, column}}, true, nil
+}
+
+// parseCondition parses the "field<op>value" form of a summary condition, e.g.
+// "trx_type=income", into a ComparisonFilter.
+func parseCondition(condition string) (Filter, error) {
+	for i := 0; i < len(condition); i++ {
+		for _, operator := range []string{"!=", ">=", "<=", "=", ">", "<"} {
+			if strings.HasPrefix(condition[i:], operator) && i > 0 {
+				return ComparisonFilter{Field: condition[:i], Operator: operator, Value: condition[i+len(operator):]}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: invalid condition %q", ErrInvalidSummary, condition)
+}
@@ -0,0 +1,144 @@
+package pagination
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CountStrategy controls how the total number of rows is computed.
+type CountStrategy string
+
+const (
+	// CountExact runs a full COUNT(*) over the matching rows.
+	CountExact CountStrategy = "exact"
+	// CountCapped stops counting after CountLimit rows.
+	CountCapped CountStrategy = "capped"
+	// CountEstimated reads planner statistics instead of counting, falling back to CountExact
+	// when the dialect has no statistics available.
+	CountEstimated CountStrategy = "estimated"
+	// CountNone skips counting and only reports whether a next page exists.
+	CountNone CountStrategy = "none"
+)
+
+// count computes the number of rows (or groups, when grouping) matched by the paginator and
+// the strategy that produced it. For CountCapped the returned value is at most CountLimit+1.
+func (p *Paginator) count(ctx context.Context, model interface{}) (int64, CountStrategy, error) {
+	var total int64
+	query := p.query(ctx).Model(model)
+
+	switch p.CountStrategy {
+	case CountCapped:
+		err := p.newQuery(ctx).
+			Table("(?) AS capped", p.countRows(query).Limit(int(p.CountLimit)+1)).
+			Count(&total).Error
+		return total, CountCapped, err
+
+	case CountEstimated:
+		estimate, ok, err := p.estimate(ctx, model)
+		if err != nil || ok {
+			return estimate, CountEstimated, err
+		}
+	}
+
+	if len(p.Groups) > 0 {
+		// Count the groups themselves rather than the rows behind them
+		err := p.newQuery(ctx).Table("(?) AS grouped", p.countRows(query)).Count(&total).Error
+		return total, CountExact, err
+	}
+
+	err := query.Count(&total).Error
+	return total, CountExact, err
+}
+
+// countRows selects the minimal columns needed to count the rows of query in a subquery.
+func (p *Paginator) countRows(query *gorm.DB) *gorm.DB {
+	if len(p.Groups) > 0 {
+		return query.Clauses(clause.Select{Columns: p.groupByClause().Columns})
+	}
+	return query.Select("1")
+}
+
+// newQuery returns a query on the paginator connection without any of its conditions.
+func (p *Paginator) newQuery(ctx context.Context) *gorm.DB {
+	return p.DB.Session(&gorm.Session{NewDB: true, Context: ctx})
+}
+
+// estimate reads the row estimate from the database statistics. It reports false when the
+// dialect is not supported or no statistics have been gathered yet.
+func (p *Paginator) estimate(ctx context.Context, model interface{}) (int64, bool, error) {
+	switch p.dialect() {
+	case "postgres":
+		// Ask the planner how many rows the filtered query would return
+		stmt := p.query(ctx).Session(&gorm.Session{DryRun: true}).Model(model).Find(model).Statement
+		if stmt.Error != nil {
+			return 0, false, stmt.Error
+		}
+
+		var plan string
+		err := stmt.ConnPool.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+stmt.SQL.String(), stmt.Vars...).Scan(&plan)
+		if err != nil {
+			return 0, false, err
+		}
+
+		var plans []struct {
+			Plan struct {
+				Rows float64 `json:"Plan Rows"`
+			} `json:"Plan"`
+		}
+		if err := json.Unmarshal([]byte(plan), &plans); err != nil || len(plans) == 0 {
+			return 0, false, err
+		}
+		return int64(plans[0].Plan.Rows), true, nil
+
+	case "sqlite":
+		// Table statistics say nothing about a filtered or grouped subset
+		if _, ok := p.filteredQuery(ctx).Statement.Clauses["WHERE"]; ok || len(p.Groups) > 0 {
+			return 0, false, nil
+		}
+
+		// sqlite_stat1 only exists once ANALYZE has been run
+		stmt := &gorm.Statement{DB: p.DB}
+		table := p.DB.Statement.Table
+		if table == "" {
+			if err := stmt.Parse(model); err != nil {
+				return 0, false, err
+			}
+			table = stmt.Table
+		}
+
+		// Missing statistics surface as an error, which simply means there is nothing to estimate from
+		var stat string
+		err := p.DB.Statement.ConnPool.
+			QueryRowContext(ctx, "SELECT stat FROM sqlite_stat1 WHERE tbl = ? LIMIT 1", table).
+			Scan(&stat)
+		if err != nil {
+			return 0, false, nil
+		}
+
+		// The first number of a stat row is the number of rows in the table
+		fields := strings.Fields(stat)
+		if len(fields) == 0 {
+			return 0, false, nil
+		}
+		rows, err := strconv.ParseInt(fields[0], 10, 64)
+		return rows, err == nil, nil
+	}
+
+	return 0, false, nil
+}
+
+// trimRows cuts the slice pointed to by result down to limit rows and reports whether it was longer.
+func trimRows(result interface{}, limit int) (reflect.Value, bool) {
+	rows := reflect.Indirect(reflect.ValueOf(result))
+	if rows.Kind() != reflect.Slice || rows.Len() <= limit {
+		return rows, false
+	}
+	rows.Set(rows.Slice(0, limit))
+	return rows, true
+}
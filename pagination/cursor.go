@@ -0,0 +1,320 @@
+package pagination
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// cursorToken is the decoded form of the opaque cursor handed to clients.
+type cursorToken struct {
+	Fields   []string          `json:"f"`
+	Values   []json.RawMessage `json:"v"`
+	Backward bool              `json:"b,omitempty"`
+}
+
+// encodeCursor serializes the sort key values of a row into an opaque cursor.
+func encodeCursor(keys []OrderBy, values []interface{}, backward bool) (string, error) {
+	token := cursorToken{Backward: backward}
+	for i, key := range keys {
+		raw, err := json.Marshal(values[i])
+		if err != nil {
+			return "", err
+		}
+		token.Fields = append(token.Fields, key.Field)
+		token.Values = append(token.Values, raw)
+	}
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor parses an opaque cursor and checks it was issued for the same sort keys.
+func decodeCursor(cursor string, keys []OrderBy) (*cursorToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var token cursorToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if len(token.Fields) != len(keys) || len(token.Values) != len(keys) {
+		return nil, ErrInvalidCursor
+	}
+	for i, key := range keys {
+		if token.Fields[i] != key.Field {
+			return nil, ErrInvalidCursor
+		}
+	}
+	return &token, nil
+}
+
+// cursorKeys collects the orderings used to seek, appending the primary key as a tiebreaker.
+func (p *Paginator) cursorKeys(sch *schema.Schema) ([]OrderBy, error) {
+	var keys []OrderBy
+	for _, ordering := range p.Orderings {
+		switch o := ordering.(type) {
+		case OrderBy:
+			keys = append(keys, o)
+		case *OrderBy:
+			keys = append(keys, *o)
+		default:
+			return nil, ErrCursorOrdering
+		}
+	}
+	for _, sort := range p.Sort {
+		orders, err := parseSort(sort)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, orders...)
+	}
+
+	if pk := sch.PrioritizedPrimaryField; pk != nil {
+		for _, key := range keys {
+			if field := lookUpField(sch, key.Field); field == pk {
+				return keys, nil
+			}
+		}
+		order := OrderBy{Field: pk.DBName, Direction: "asc"}
+		if len(keys) > 0 && keys[len(keys)-1].desc() {
+			order.Direction = "desc"
+		}
+		keys = append(keys, order)
+	}
+	return keys, nil
+}
+
+// lookUpField finds the schema field for a possibly table-qualified column name.
+func lookUpField(sch *schema.Schema, column string) *schema.Field {
+	if i := strings.LastIndex(column, "."); i >= 0 {
+		column = column[i+1:]
+	}
+	return sch.LookUpField(column)
+}
+
+// seekCondition builds the keyset predicate selecting rows after (or before) the cursor values.
+// Nullable keys compare with IS NULL rather than = or <, their NULLs sorting last when
+// scanning ascending and first when scanning descending, as seekOrder orders them.
+func seekCondition(keys []OrderBy, values []interface{}, nullable []bool, backward bool) (string, []interface{}) {
+	var (
+		conditions []string
+		vars       []interface{}
+	)
+	for i, key := range keys {
+		var (
+			parts     []string
+			partVars  []interface{}
+			column    = clause.Column{Name: key.Field}
+			desc      = key.desc() != backward
+			following = true
+		)
+		for j := 0; j < i; j++ {
+			if values[j] == nil {
+				parts = append(parts, "? IS NULL")
+				partVars = append(partVars, clause.Column{Name: keys[j].Field})
+			} else {
+				parts = append(parts, "? = ?")
+				partVars = append(partVars, clause.Column{Name: keys[j].Field}, values[j])
+			}
+		}
+
+		switch {
+		case !nullable[i] || values[i] != nil && desc:
+			operator := ">"
+			if desc {
+				operator = "<"
+			}
+			parts = append(parts, "? "+operator+" ?")
+			partVars = append(partVars, column, values[i])
+		case values[i] != nil:
+			parts = append(parts, "(? > ? OR ? IS NULL)")
+			partVars = append(partVars, column, values[i], column)
+		case desc:
+			parts = append(parts, "? IS NOT NULL")
+			partVars = append(partVars, column)
+		default:
+			// Nothing follows the NULLs when scanning ascending
+			following = false
+		}
+
+		if following {
+			conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+			vars = append(vars, partVars...)
+		}
+	}
+	if len(conditions) == 0 {
+		return "1 = 0", nil
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", vars
+}
+
+// seekOrder orders on the keys, scanning them in reverse when backward. NULLs of nullable keys
+// sort last when scanning ascending and first when scanning descending on every dialect.
+func seekOrder(keys []OrderBy, nullable []bool, backward bool) clause.OrderBy {
+	var (
+		parts []string
+		vars  []interface{}
+	)
+	for i, key := range keys {
+		column := clause.Column{Name: key.Field}
+		direction := " ASC"
+		if key.desc() != backward {
+			direction = " DESC"
+		}
+		if nullable[i] {
+			parts = append(parts, "? IS NULL"+direction)
+			vars = append(vars, column)
+		}
+		parts = append(parts, "?"+direction)
+		vars = append(vars, column)
+	}
+	return clause.OrderBy{Expression: clause.Expr{SQL: strings.Join(parts, ", "), Vars: vars}}
+}
+
+// scannerType is the type of sql.Scanner, implemented by nullable types such as sql.NullString.
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// nullable reports whether the column of field may hold NULL, judging by its Go type.
+func nullable(field *schema.Field) bool {
+	if field.PrimaryKey || field.NotNull {
+		return false
+	}
+	return field.FieldType.Kind() == reflect.Ptr || reflect.PtrTo(field.FieldType).Implements(scannerType)
+}
+
+// isNull reports whether a decoded cursor value stands for NULL.
+func isNull(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	if valuer, ok := value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		return err == nil && v == nil
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr {
+		return v.IsNil()
+	}
+	return false
+}
+
+// cursorSeek describes the keyset a cursor query seeks on.
+type cursorSeek struct {
+	keys     []OrderBy
+	fields   []*schema.Field
+	token    *cursorToken
+	backward bool
+}
+
+// seek restricts query to the rows after (or before) the paginator cursor and orders it on the
+// sort keys of model.
+func (p *Paginator) seek(query *gorm.DB, model interface{}) (*gorm.DB, *cursorSeek, error) {
+	stmt := &gorm.Statement{DB: p.DB}
+	if err := stmt.Parse(model); err != nil {
+		return nil, nil, err
+	}
+
+	keys, err := p.cursorKeys(stmt.Schema)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields := make([]*schema.Field, len(keys))
+	nullables := make([]bool, len(keys))
+	for i, key := range keys {
+		if fields[i] = lookUpField(stmt.Schema, key.Field); fields[i] == nil {
+			return nil, nil, ErrCursorOrdering
+		}
+		nullables[i] = nullable(fields[i])
+	}
+
+	var token *cursorToken
+	if p.Cursor != "" {
+		if token, err = decodeCursor(p.Cursor, keys); err != nil {
+			return nil, nil, err
+		}
+
+		values := make([]interface{}, len(keys))
+		for i, field := range fields {
+			value := reflect.New(field.FieldType)
+			if err := json.Unmarshal(token.Values[i], value.Interface()); err != nil {
+				return nil, nil, ErrInvalidCursor
+			}
+			values[i] = value.Elem().Interface()
+			if nullables[i] && isNull(values[i]) {
+				values[i] = nil
+			}
+		}
+
+		condition, vars := seekCondition(keys, values, nullables, token.Backward)
+		query = query.Where(condition, vars...)
+	}
+
+	backward := token != nil && token.Backward
+	query = query.Order(seekOrder(keys, nullables, backward))
+	return query, &cursorSeek{keys: keys, fields: fields, token: token, backward: backward}, nil
+}
+
+// paginateCursor fetches one page using keyset pagination and fills the cursors of res.
+func (p *Paginator) paginateCursor(query *gorm.DB, result interface{}, res *Result) error {
+	query, seek, err := p.seek(query, result)
+	if err != nil {
+		return err
+	}
+	keys, fields, token, backward := seek.keys, seek.fields, seek.token, seek.backward
+
+	// Fetch one extra row to know whether another page follows
+	if err := query.Limit(p.PageSize + 1).Find(result).Error; err != nil {
+		return err
+	}
+
+	rows, hasMore := trimRows(result, p.PageSize)
+	if rows.Kind() != reflect.Slice {
+		return nil
+	}
+
+	res.HasNext = hasMore || backward
+	if backward {
+		swap := reflect.Swapper(rows.Interface())
+		for i, j := 0, rows.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+	if rows.Len() == 0 {
+		return nil
+	}
+
+	rowValues := func(row reflect.Value) []interface{} {
+		values := make([]interface{}, len(fields))
+		for i, field := range fields {
+			values[i], _ = field.ValueOf(context.Background(), reflect.Indirect(row))
+		}
+		return values
+	}
+
+	if hasMore || backward {
+		if res.NextCursor, err = encodeCursor(keys, rowValues(rows.Index(rows.Len()-1)), false); err != nil {
+			return err
+		}
+	}
+	if (token != nil && !backward) || (backward && hasMore) {
+		if res.PrevCursor, err = encodeCursor(keys, rowValues(rows.Index(0)), true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
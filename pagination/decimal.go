@@ -0,0 +1,150 @@
+package pagination
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DecimalEncoding selects how a Decimal is written to JSON.
+type DecimalEncoding string
+
+const (
+	// DecimalString encodes a Decimal as a JSON string, e.g. "1234.50", which every JSON
+	// decoder preserves exactly.
+	DecimalString DecimalEncoding = "string"
+	// DecimalNumber encodes a Decimal as a JSON number with the exact digits, e.g. 1234.50.
+	DecimalNumber DecimalEncoding = "number"
+)
+
+// Decimal is an exact numeric summary value, holding the digits as returned by the database.
+// Unlike float64 it tells SQL NULL, such as a SUM over no rows, apart from zero.
+type Decimal struct {
+	Value    string          // exact digits, e.g. "1234.50"
+	Valid    bool            // false when the database returned NULL
+	Encoding DecimalEncoding // JSON form, DecimalString when empty
+}
+
+// String returns the digits of d, or "NULL".
+func (d Decimal) String() string {
+	if !d.Valid {
+		return "NULL"
+	}
+	return d.Value
+}
+
+// Float64 converts d to a float64, losing precision where float64 cannot represent it. NULL
+// converts to 0.
+func (d Decimal) Float64() (float64, error) {
+	if !d.Valid {
+		return 0, nil
+	}
+	return strconv.ParseFloat(d.Value, 64)
+}
+
+// Scan implements sql.Scanner.
+func (d *Decimal) Scan(src interface{}) error {
+	d.Valid = true
+	switch v := src.(type) {
+	case nil:
+		d.Value, d.Valid = "", false
+	case []byte:
+		d.Value = string(v)
+	case string:
+		d.Value = v
+	case int64:
+		d.Value = strconv.FormatInt(v, 10)
+	case float64:
+		d.Value = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("pagination: cannot scan %T into Decimal", src)
+	}
+	return nil
+}
+
+// MarshalJSON writes d as null, or in the form selected by its Encoding.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	switch {
+	case !d.Valid:
+		return []byte("null"), nil
+	case d.Encoding == DecimalNumber:
+		return []byte(d.Value), nil
+	}
+	return json.Marshal(d.Value)
+}
+
+// UnmarshalJSON reads a Decimal written in either encoding, or null.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Decimal{}
+		return nil
+	}
+
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return err
+	}
+	d.Value, d.Valid = number.String(), true
+	if data[0] != '"' {
+		d.Encoding = DecimalNumber
+	}
+	return nil
+}
+
+// subtractDecimals subtracts the non-NULL values of bs from a exactly, keeping the largest
+// number of decimals. The result is NULL when a is NULL or not a number.
+func subtractDecimals(a Decimal, bs ...Decimal) Decimal {
+	result, ok := new(big.Rat).SetString(a.Value)
+	if !a.Valid || !ok {
+		return Decimal{Encoding: a.Encoding}
+	}
+
+	scale := decimals(a.Value)
+	for _, b := range bs {
+		r, ok := new(big.Rat).SetString(b.Value)
+		if !b.Valid || !ok {
+			continue
+		}
+		result.Sub(result, r)
+		if d := decimals(b.Value); d > scale {
+			scale = d
+		}
+	}
+	return Decimal{Value: result.FloatString(scale), Valid: true, Encoding: a.Encoding}
+}
+
+// decimals returns the number of digits after the decimal point of a decimal string.
+func decimals(value string) int {
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		return len(value) - i - 1
+	}
+	return 0
+}
+
+// sumDests returns n scan destinations for SUM columns: Decimals with encoding when it is set,
+// float64s otherwise.
+func sumDests(n int, encoding DecimalEncoding) []interface{} {
+	dests := make([]interface{}, n)
+	for i := range dests {
+		if encoding != "" {
+			dests[i] = &Decimal{Encoding: encoding}
+		} else {
+			dests[i] = &sql.NullFloat64{}
+		}
+	}
+	return dests
+}
+
+// sumValue returns the value scanned into a sumDests destination, a Decimal or a float64 with
+// NULL as 0, together with its float64 approximation.
+func sumValue(dest interface{}) (interface{}, float64) {
+	if d, ok := dest.(*Decimal); ok {
+		f, _ := d.Float64()
+		return *d, f
+	}
+	f := dest.(*sql.NullFloat64).Float64
+	return f, f
+}
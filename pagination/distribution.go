@@ -0,0 +1,191 @@
+package pagination
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Top limits a distribution to the n largest buckets, folding the remaining rows into a
+// trailing bucket flagged with "other": true.
+func (s SummarySpec) Top(n int) SummarySpec {
+	s.Limit = n
+	return s
+}
+
+// RankedBy ranks and weighs distribution buckets by the sum of field instead of the row count.
+// Each bucket then also reports "<field>_sum".
+func (s SummarySpec) RankedBy(field string) SummarySpec {
+	s.RankBy = field
+	return s
+}
+
+// Sorted orders distribution buckets by their count (or RankBy sum), "asc" or "desc". Without
+// an order, limited or ranked distributions are sorted descending and the others by value.
+func (s SummarySpec) Sorted(order string) SummarySpec {
+	s.Order = order
+	return s
+}
+
+// validateDistribution checks the limit and sort order of a distribution.
+func (s SummarySpec) validateDistribution() error {
+	if s.Limit < 0 {
+		return fmt.Errorf("%w: negative distribution limit", ErrInvalidSummary)
+	}
+	switch strings.ToLower(s.Order) {
+	case "", "asc", "desc":
+		return nil
+	}
+	return fmt.Errorf("%w: unknown distribution order %q", ErrInvalidSummary, s.Order)
+}
+
+// distribution counts the rows per distinct value of spec.Field. Every bucket reports its
+// share of the total as "percent", measured by count or by the RankBy sum.
+func distribution(db *gorm.DB, spec SummarySpec) ([]map[string]interface{}, error) {
+	db = db.Session(&gorm.Session{})
+
+	field := clause.Column{Name: spec.Field}
+	metric, columns := clause.Expr{SQL: "COUNT(*)"}, clause.Expr{SQL: "COUNT(*) AS count"}
+	if spec.RankBy != "" {
+		metric = clause.Expr{SQL: "SUM(?)", Vars: []interface{}{clause.Column{Name: spec.RankBy}}}
+		columns = clause.Expr{SQL: "COUNT(*) AS count, ? AS total", Vars: []interface{}{metric}}
+	}
+
+	// Totals over all rows, for the percentages and the "other" bucket. Without a limit every
+	// row is in a bucket, so they are summed from the buckets instead
+	var (
+		totalCount int64
+		totalSum   interface{} = 0.0
+	)
+	if spec.Limit > 0 {
+		totals := []scalarAggregation{{key: "count", sql: "COUNT(*)", count: true}}
+		if spec.RankBy != "" {
+			totals = append(totals, scalarAggregation{key: "total", sql: metric.SQL, vars: metric.Vars, decimal: spec.decimal})
+		}
+		values, err := scanScalars(db, totals)
+		if err != nil {
+			return nil, err
+		}
+		totalCount = values[0].(int64)
+		if spec.RankBy != "" {
+			totalSum = values[1]
+		}
+	}
+
+	query := db.Clauses(
+		clause.Select{Expression: clause.Expr{SQL: "?, ?", Vars: []interface{}{field, columns}}},
+		clause.GroupBy{Columns: []clause.Column{field}},
+	)
+	order := strings.ToLower(spec.Order)
+	if order == "" && (spec.Limit > 0 || spec.RankBy != "") {
+		order = "desc"
+	}
+	orderBy := clause.Expr{SQL: "?", Vars: []interface{}{field}}
+	if order != "" {
+		orderBy = clause.Expr{SQL: "? " + strings.ToUpper(order) + ", ?", Vars: []interface{}{metric, field}}
+	}
+	query = query.Order(clause.OrderBy{Expression: orderBy})
+	if spec.Limit > 0 {
+		query = query.Limit(spec.Limit)
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		buckets = make([]map[string]interface{}, 0)
+		counts  []int64
+		sums    []interface{}
+	)
+	for rows.Next() {
+		var (
+			value interface{}
+			count int64
+			sum   = sumDests(1, spec.decimal)[0]
+			dest  = []interface{}{&value, &count}
+		)
+		if spec.RankBy != "" {
+			dest = append(dest, sum)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, map[string]interface{}{spec.Field: scannedValue(value)})
+		counts = append(counts, count)
+		sums = append(sums, sum)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var (
+		covered  int64
+		ranked   float64
+		decimals []Decimal
+	)
+	for i := range buckets {
+		covered += counts[i]
+		if d, ok := sums[i].(*Decimal); ok {
+			decimals = append(decimals, *d)
+		}
+		_, sum := sumValue(sums[i])
+		ranked += sum
+	}
+	total, _ := totalSum.(float64)
+	if d, ok := totalSum.(Decimal); ok {
+		total, _ = d.Float64()
+	}
+	if spec.Limit == 0 {
+		totalCount, total = covered, ranked
+	}
+
+	for i, bucket := range buckets {
+		value, sum := sumValue(sums[i])
+		fillDistributionBucket(spec, bucket, counts[i], value, sum, totalCount, total)
+	}
+	if rest := totalCount - covered; spec.Limit > 0 && rest > 0 {
+		other := map[string]interface{}{spec.Field: nil, "other": true}
+		var value interface{} = total - ranked
+		if d, ok := totalSum.(Decimal); ok {
+			// Subtract exactly, as the sums are reported
+			value = subtractDecimals(d, decimals...)
+		}
+		fillDistributionBucket(spec, other, rest, value, total-ranked, totalCount, total)
+		buckets = append(buckets, other)
+	}
+	return buckets, nil
+}
+
+// fillDistributionBucket sets the count, sum and share of the total of a distribution bucket.
+// The sum is reported as value, a float64 or a Decimal, and weighs the bucket as sum.
+func fillDistributionBucket(spec SummarySpec, bucket map[string]interface{}, count int64, value interface{}, sum float64, totalCount int64, totalSum float64) {
+	bucket["count"] = count
+	bucket["percent"] = percentOf(float64(count), float64(totalCount))
+	if spec.RankBy != "" {
+		bucket[spec.RankBy+"_sum"] = value
+		bucket["percent"] = percentOf(sum, totalSum)
+	}
+}
+
+// scannedValue converts a column scanned into an interface{}, turning text returned as bytes
+// into a string.
+func scannedValue(value interface{}) interface{} {
+	if raw, ok := value.([]byte); ok {
+		return string(raw)
+	}
+	return value
+}
+
+// percentOf returns part as a percentage of total rounded to two decimals, or 0 when total is 0.
+func percentOf(part, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(part/total*10000) / 100
+}
@@ -3,6 +3,12 @@ package pagination
 import "errors"
 
 var (
-	ErrInvalidPageSize = errors.New("invalid page size, must be greater than 0")
-	ErrInvalidPage     = errors.New("invalid page number, must be greater than 0")
+	ErrInvalidPageSize    = errors.New("invalid page size, must be greater than 0")
+	ErrInvalidPage        = errors.New("invalid page number, must be greater than 0")
+	ErrInvalidCursor      = errors.New("invalid cursor")
+	ErrCursorOrdering     = errors.New("cursor pagination only supports OrderBy orderings")
+	ErrQueryTimeout       = errors.New("query timed out")
+	ErrUnknownAggregation = errors.New("unknown aggregation type")
+	ErrInvalidSummary     = errors.New("invalid summary")
+	ErrUnsupportedFilter  = errors.New("filter cannot be expressed as a condition")
 )
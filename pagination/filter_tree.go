@@ -0,0 +1,123 @@
+package pagination
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AndFilter matches the rows matching every one of its filters.
+type AndFilter struct {
+	Filters []Filter
+}
+
+// OrFilter matches the rows matching at least one of its filters.
+type OrFilter struct {
+	Filters []Filter
+}
+
+// NotFilter matches the rows not matching its filter.
+type NotFilter struct {
+	Filter Filter
+}
+
+// And combines filters with AND. Filters nest arbitrarily, e.g.
+//
+//	Or(
+//		And(ComparisonFilter{"trx_type", "=", "income"}, ComparisonFilter{"trx_amount", ">", 100}),
+//		And(ComparisonFilter{"trx_type", "=", "expense"}, ComparisonFilter{"account_number", "=", "X"}),
+//	)
+func And(filters ...Filter) AndFilter {
+	return AndFilter{Filters: filters}
+}
+
+// Or combines filters with OR.
+func Or(filters ...Filter) OrFilter {
+	return OrFilter{Filters: filters}
+}
+
+// Not negates filter.
+func Not(filter Filter) NotFilter {
+	return NotFilter{Filter: filter}
+}
+
+func (f AndFilter) Apply(db *gorm.DB) *gorm.DB {
+	return applyExpression(db, f)
+}
+
+// Expression combines the filters with AND. Without filters it matches every row.
+func (f AndFilter) Expression(db *gorm.DB) (clause.Expression, error) {
+	exprs, err := predicates(db, f.Filters)
+	switch {
+	case err != nil:
+		return nil, err
+	case len(exprs) == 0:
+		return matchAll{}, nil
+	case len(exprs) == 1:
+		return exprs[0], nil
+	}
+	return clause.AndConditions{Exprs: exprs}, nil
+}
+
+func (f OrFilter) Apply(db *gorm.DB) *gorm.DB {
+	return applyExpression(db, f)
+}
+
+// Expression combines the filters with OR. Without filters it matches every row, like an
+// empty FilterManager OR group.
+func (f OrFilter) Expression(db *gorm.DB) (clause.Expression, error) {
+	exprs, err := predicates(db, f.Filters)
+	switch {
+	case err != nil:
+		return nil, err
+	case len(exprs) == 0:
+		return matchAll{}, nil
+	case len(exprs) == 1:
+		// GORM joins a lone OR condition to the previous conditions with OR
+		return exprs[0], nil
+	}
+	return clause.OrConditions{Exprs: exprs}, nil
+}
+
+func (f AndFilter) fields() []string {
+	return groupFields(f.Filters)
+}
+
+func (f OrFilter) fields() []string {
+	return groupFields(f.Filters)
+}
+
+func (f NotFilter) fields() []string {
+	return filterFields(f.Filter)
+}
+
+func (f NotFilter) Apply(db *gorm.DB) *gorm.DB {
+	return applyExpression(db, f)
+}
+
+func (f NotFilter) Expression(db *gorm.DB) (clause.Expression, error) {
+	exprs, err := predicates(db, []Filter{f.Filter})
+	if err != nil {
+		return nil, err
+	}
+	return clause.Expr{SQL: "NOT ?", Vars: []interface{}{exprs[0]}}, nil
+}
+
+// predicates compiles each filter into a parenthesized predicate, so that it keeps its meaning
+// whatever it is combined with.
+func predicates(db *gorm.DB, filters []Filter) ([]clause.Expression, error) {
+	exprs := make([]clause.Expression, len(filters))
+	for i, filter := range filters {
+		expr, err := FilterExpression(db, filter)
+		if err != nil {
+			return nil, err
+		}
+		switch expr.(type) {
+		case clause.AndConditions, clause.OrConditions:
+			// Already parenthesized when built
+			exprs[i] = expr
+		default:
+			exprs[i] = clause.Expr{SQL: "(?)", Vars: []interface{}{expr}}
+		}
+	}
+	return exprs, nil
+}
@@ -1,8 +1,11 @@
 package pagination
 
 import (
-	"gorm.io/gorm"
+	"fmt"
 	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Filter defines an interface for applying filters.
@@ -10,6 +13,65 @@ type Filter interface {
 	Apply(db *gorm.DB) *gorm.DB
 }
 
+// ExpressionFilter is a Filter that can also be expressed as a standalone SQL condition, which
+// lets it take part in OR and NOT groups. All built-in filters implement it.
+type ExpressionFilter interface {
+	Filter
+	Expression(db *gorm.DB) (clause.Expression, error)
+}
+
+// FilterExpression returns the SQL condition of filter. Filters that do not implement
+// ExpressionFilter are applied to an empty query and their WHERE conditions used; a filter
+// adding no WHERE condition cannot be expressed and yields ErrUnsupportedFilter.
+func FilterExpression(db *gorm.DB, filter Filter) (clause.Expression, error) {
+	if filter, ok := filter.(ExpressionFilter); ok {
+		return filter.Expression(db)
+	}
+	if filter == nil {
+		return nil, fmt.Errorf("%w: nil filter", ErrUnsupportedFilter)
+	}
+
+	applied := filter.Apply(db.Session(&gorm.Session{NewDB: true}))
+	if applied.Error != nil {
+		return nil, applied.Error
+	}
+
+	where, ok := applied.Statement.Clauses["WHERE"].Expression.(clause.Where)
+	if !ok || len(where.Exprs) == 0 {
+		return nil, fmt.Errorf("%w: %T adds no condition", ErrUnsupportedFilter, filter)
+	}
+	return predicate(where), nil
+}
+
+// predicate builds the conditions of a WHERE clause without the WHERE keyword. Embedded as a
+// variable, a clause.Where would otherwise be built as a complete clause.
+type predicate clause.Where
+
+func (p predicate) Build(builder clause.Builder) {
+	clause.Where(p).Build(builder)
+}
+
+// matchAll is the condition of an empty filter group. It matches every row and is left out
+// when the group is applied on its own.
+type matchAll struct{}
+
+func (matchAll) Build(builder clause.Builder) {
+	builder.WriteString("1 = 1")
+}
+
+// applyExpression adds the condition of filter to db.
+func applyExpression(db *gorm.DB, filter ExpressionFilter) *gorm.DB {
+	expr, err := filter.Expression(db)
+	if err != nil {
+		db.AddError(err)
+		return db
+	}
+	if _, ok := expr.(matchAll); ok {
+		return db
+	}
+	return db.Where(expr)
+}
+
 // FilterManager manages multiple filters and applies them.
 type FilterManager struct {
 	AndFilters []Filter
@@ -28,44 +90,25 @@ func (fm *FilterManager) AddOrFilter(filter Filter) {
 
 // Apply applies all the filters in the FilterManager to the query.
 func (fm *FilterManager) Apply(db *gorm.DB) *gorm.DB {
-	// Apply AND filters
-	for _, filter := range fm.AndFilters {
-		db = filter.Apply(db)
-	}
+	return applyExpression(db, fm)
+}
 
-	// Build OR filters using raw SQL
+// Expression returns the AND filters combined with AND, followed by the OR filters as one
+// OR group.
+func (fm *FilterManager) Expression(db *gorm.DB) (clause.Expression, error) {
+	filters := append([]Filter(nil), fm.AndFilters...)
 	if len(fm.OrFilters) > 0 {
-		var orConditions []string
-		var orValues []interface{}
-
-		// Build the OR conditions as raw SQL
-		for _, filter := range fm.OrFilters {
-			query, args := filterToSQL(filter)
-			orConditions = append(orConditions, query)
-			orValues = append(orValues, args...)
-		}
-
-		// Combine OR conditions
-		if len(orConditions) > 0 {
-			orClause := "(" + strings.Join(orConditions, " OR ") + ")"
-			db = db.Where(orClause, orValues...)
-		}
+		filters = append(filters, Or(fm.OrFilters...))
 	}
-
-	return db
+	return And(filters...).Expression(db)
 }
 
-// Helper function to convert Filter to raw SQL
-func filterToSQL(filter Filter) (string, []interface{}) {
-	switch f := filter.(type) {
-	case ComparisonFilter:
-		return f.Field + " " + f.Operator + " ?", []interface{}{f.Value}
-	default:
-		return "", nil
-	}
+func (fm *FilterManager) fields() []string {
+	return append(groupFields(fm.AndFilters), groupFields(fm.OrFilters)...)
 }
 
-// DateRangeFilter applies a date range filter.
+// DateRangeFilter applies a date range filter. Like in the other built-in filters, Field is
+// quoted as a column name, optionally qualified by its table.
 type DateRangeFilter struct {
 	Field     string
 	StartDate string
@@ -73,10 +116,19 @@ type DateRangeFilter struct {
 }
 
 func (f DateRangeFilter) Apply(db *gorm.DB) *gorm.DB {
-	return db.Where(f.Field+" BETWEEN ? AND ?", f.StartDate, f.EndDate)
+	return applyExpression(db, f)
 }
 
-// ComparisonFilter allows filtering with different comparison operators.
+func (f DateRangeFilter) Expression(*gorm.DB) (clause.Expression, error) {
+	return clause.Expr{SQL: "? BETWEEN ? AND ?", Vars: []interface{}{clause.Column{Name: f.Field}, f.StartDate, f.EndDate}}, nil
+}
+
+func (f DateRangeFilter) fields() []string {
+	return []string{f.Field}
+}
+
+// ComparisonFilter allows filtering with different comparison operators. Operators outside the
+// examples below are rejected with a ValidationError.
 type ComparisonFilter struct {
 	Field    string
 	Operator string // Examples: "=", ">", "<", ">=", "<=", "!="
@@ -84,7 +136,18 @@ type ComparisonFilter struct {
 }
 
 func (f ComparisonFilter) Apply(db *gorm.DB) *gorm.DB {
-	return db.Where(f.Field+" "+f.Operator+" ?", f.Value)
+	return applyExpression(db, f)
+}
+
+func (f ComparisonFilter) Expression(*gorm.DB) (clause.Expression, error) {
+	if err := checkOperator(f.Operator); err != nil {
+		return nil, err
+	}
+	return clause.Expr{SQL: "? " + f.Operator + " ?", Vars: []interface{}{clause.Column{Name: f.Field}, f.Value}}, nil
+}
+
+func (f ComparisonFilter) fields() []string {
+	return []string{f.Field}
 }
 
 // StatusFilter applies a status filter (used as an example of IN clause).
@@ -94,15 +157,202 @@ type StatusFilter struct {
 }
 
 func (f StatusFilter) Apply(db *gorm.DB) *gorm.DB {
-	return db.Where(f.Field+" IN ?", f.Statuses)
+	return applyExpression(db, f)
+}
+
+func (f StatusFilter) Expression(*gorm.DB) (clause.Expression, error) {
+	return clause.Expr{SQL: "? IN ?", Vars: []interface{}{clause.Column{Name: f.Field}, f.Statuses}}, nil
 }
 
-// SearchFilter applies a search filter using LIKE (for string searches).
+func (f StatusFilter) fields() []string {
+	return []string{f.Field}
+}
+
+// SearchFilter applies a search filter using LIKE (for string searches), matching the rows
+// whose field contains Value.
 type SearchFilter struct {
 	Field string
 	Value string
 }
 
 func (f SearchFilter) Apply(db *gorm.DB) *gorm.DB {
-	return db.Where(f.Field+" LIKE ?", "%"+f.Value+"%")
+	return applyExpression(db, f)
+}
+
+// Expression matches Value literally: its "%" and "_" are escaped rather than used as wildcards.
+func (f SearchFilter) Expression(*gorm.DB) (clause.Expression, error) {
+	return likeExpr(f.Field, "%"+escapeLike(f.Value)+"%", false), nil
+}
+
+func (f SearchFilter) fields() []string {
+	return []string{f.Field}
+}
+
+// NullFilter matches the rows whose field IS NULL, or IS NOT NULL when Not is set.
+type NullFilter struct {
+	Field string
+	Not   bool
+}
+
+func (f NullFilter) Apply(db *gorm.DB) *gorm.DB {
+	return applyExpression(db, f)
+}
+
+func (f NullFilter) Expression(*gorm.DB) (clause.Expression, error) {
+	if f.Not {
+		return clause.Expr{SQL: "? IS NOT NULL", Vars: []interface{}{clause.Column{Name: f.Field}}}, nil
+	}
+	return clause.Expr{SQL: "? IS NULL", Vars: []interface{}{clause.Column{Name: f.Field}}}, nil
+}
+
+func (f NullFilter) fields() []string {
+	return []string{f.Field}
+}
+
+// InFilter matches the rows whose field is one of Values, or none of them when Not is set.
+// Without values it matches no rows, or every row when Not is set.
+type InFilter struct {
+	Field  string
+	Values []interface{}
+	Not    bool
+}
+
+func (f InFilter) Apply(db *gorm.DB) *gorm.DB {
+	return applyExpression(db, f)
+}
+
+func (f InFilter) Expression(*gorm.DB) (clause.Expression, error) {
+	switch {
+	case len(f.Values) == 0 && f.Not:
+		return matchAll{}, nil
+	case len(f.Values) == 0:
+		return clause.Expr{SQL: "1 = 0"}, nil
+	case f.Not:
+		return clause.Expr{SQL: "? NOT IN ?", Vars: []interface{}{clause.Column{Name: f.Field}, f.Values}}, nil
+	}
+	return clause.Expr{SQL: "? IN ?", Vars: []interface{}{clause.Column{Name: f.Field}, f.Values}}, nil
+}
+
+func (f InFilter) fields() []string {
+	return []string{f.Field}
+}
+
+// BetweenFilter matches the rows whose field lies between Low and High, both included. Unlike
+// DateRangeFilter, the bounds keep their type, so numbers compare as numbers.
+type BetweenFilter struct {
+	Field string
+	Low   interface{}
+	High  interface{}
+}
+
+func (f BetweenFilter) Apply(db *gorm.DB) *gorm.DB {
+	return applyExpression(db, f)
+}
+
+func (f BetweenFilter) Expression(*gorm.DB) (clause.Expression, error) {
+	return clause.Expr{SQL: "? BETWEEN ? AND ?", Vars: []interface{}{clause.Column{Name: f.Field}, f.Low, f.High}}, nil
+}
+
+func (f BetweenFilter) fields() []string {
+	return []string{f.Field}
+}
+
+// StartsWithFilter matches the rows whose field starts with Value, taken literally.
+type StartsWithFilter struct {
+	Field string
+	Value string
+}
+
+func (f StartsWithFilter) Apply(db *gorm.DB) *gorm.DB {
+	return applyExpression(db, f)
+}
+
+func (f StartsWithFilter) Expression(*gorm.DB) (clause.Expression, error) {
+	return likeExpr(f.Field, escapeLike(f.Value)+"%", false), nil
+}
+
+func (f StartsWithFilter) fields() []string {
+	return []string{f.Field}
+}
+
+// EndsWithFilter matches the rows whose field ends with Value, taken literally.
+type EndsWithFilter struct {
+	Field string
+	Value string
+}
+
+func (f EndsWithFilter) Apply(db *gorm.DB) *gorm.DB {
+	return applyExpression(db, f)
+}
+
+func (f EndsWithFilter) Expression(*gorm.DB) (clause.Expression, error) {
+	return likeExpr(f.Field, "%"+escapeLike(f.Value), false), nil
+}
+
+func (f EndsWithFilter) fields() []string {
+	return []string{f.Field}
+}
+
+// ContainsFilter matches the rows whose field contains Value, taken literally and ignoring
+// case. It uses ILIKE on Postgres and compares LOWER() values elsewhere.
+type ContainsFilter struct {
+	Field string
+	Value string
+}
+
+func (f ContainsFilter) Apply(db *gorm.DB) *gorm.DB {
+	return applyExpression(db, f)
+}
+
+func (f ContainsFilter) Expression(db *gorm.DB) (clause.Expression, error) {
+	pattern := "%" + escapeLike(f.Value) + "%"
+	if db.Dialector.Name() == "postgres" {
+		return clause.Expr{SQL: "? ILIKE ? ESCAPE '" + likeEscape + "'", Vars: []interface{}{clause.Column{Name: f.Field}, pattern}}, nil
+	}
+	return clause.Expr{SQL: "LOWER(?) LIKE LOWER(?) ESCAPE '" + likeEscape + "'", Vars: []interface{}{clause.Column{Name: f.Field}, pattern}}, nil
+}
+
+func (f ContainsFilter) fields() []string {
+	return []string{f.Field}
+}
+
+// LikeFilter matches the rows whose field is LIKE Pattern, or NOT LIKE it when Not is set.
+// Pattern is passed as is, so "%" and "_" act as wildcards, and "!" escapes the next character.
+type LikeFilter struct {
+	Field   string
+	Pattern string
+	Not     bool
+}
+
+func (f LikeFilter) Apply(db *gorm.DB) *gorm.DB {
+	return applyExpression(db, f)
+}
+
+func (f LikeFilter) Expression(*gorm.DB) (clause.Expression, error) {
+	return likeExpr(f.Field, f.Pattern, f.Not), nil
+}
+
+func (f LikeFilter) fields() []string {
+	return []string{f.Field}
+}
+
+// likeEscape is the LIKE escape character. Unlike a backslash, which MySQL reads as a string
+// escape, it can be written the same way in every dialect.
+const likeEscape = "!"
+
+// likeExpr matches field against pattern, in which likeEscape escapes the next character.
+func likeExpr(field, pattern string, not bool) clause.Expr {
+	operator := " LIKE "
+	if not {
+		operator = " NOT LIKE "
+	}
+	return clause.Expr{SQL: "?" + operator + "? ESCAPE '" + likeEscape + "'", Vars: []interface{}{clause.Column{Name: field}, pattern}}
+}
+
+// likeEscaper escapes the LIKE wildcards, and the escape character itself.
+var likeEscaper = strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_")
+
+// escapeLike makes value match literally in a LIKE pattern.
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
 }
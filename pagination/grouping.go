@@ -1,5 +1,7 @@
 package pagination
 
+import "gorm.io/gorm/clause"
+
 // GroupBy applies a group by clause to the query.
 func (p *Paginator) GroupBy(fields ...string) *Paginator {
 	for _, field := range fields {
@@ -7,3 +9,14 @@ func (p *Paginator) GroupBy(fields ...string) *Paginator {
 	}
 	return p
 }
+
+// groupByClause builds the GROUP BY clause for the paginator groupings.
+func (p *Paginator) groupByClause() clause.GroupBy {
+	groupBy := clause.GroupBy{
+		Columns: make([]clause.Column, len(p.Groups)),
+	}
+	for i, group := range p.Groups {
+		groupBy.Columns[i] = clause.Column{Name: group}
+	}
+	return groupBy
+}
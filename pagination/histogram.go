@@ -0,0 +1,252 @@
+package pagination
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// bucketLayout is the textual form both dialects render date histogram buckets in.
+const bucketLayout = "2006-01-02 15:04:05"
+
+// validInterval reports whether interval is a supported date histogram bucket size.
+func validInterval(interval string) bool {
+	switch interval {
+	case "hour", "day", "week", "month":
+		return true
+	}
+	return false
+}
+
+// fixedOffset reports whether loc keeps the same UTC offset all year, i.e. observes no
+// daylight saving time.
+func fixedOffset(loc *time.Location) bool {
+	year := time.Now().Year()
+	_, winter := time.Date(year, time.January, 1, 0, 0, 0, 0, loc).Zone()
+	_, summer := time.Date(year, time.July, 1, 0, 0, 0, 0, loc).Zone()
+	return winter == summer
+}
+
+// bucketExpr truncates field to the start of its interval in loc, rendered as bucketLayout.
+// SQLite has no time zone database, so buckets there shift every row by the zone's current UTC
+// offset. That only holds for fixed-offset zones, so zones with daylight saving time are
+// rejected outside of Postgres.
+func bucketExpr(dialect string, field clause.Column, interval string, loc *time.Location) clause.Expr {
+	if dialect == "postgres" {
+		return clause.Expr{
+			SQL:  "TO_CHAR(DATE_TRUNC(?, ? AT TIME ZONE ?), 'YYYY-MM-DD HH24:MI:SS')",
+			Vars: []interface{}{interval, field, loc.String()},
+		}
+	}
+
+	_, offset := time.Now().In(loc).Zone()
+	shift := strconv.Itoa(offset/60) + " minutes"
+	switch interval {
+	case "hour":
+		return clause.Expr{SQL: "STRFTIME('%Y-%m-%d %H:00:00', ?, ?)", Vars: []interface{}{field, shift}}
+	case "week":
+		return clause.Expr{SQL: "STRFTIME('%Y-%m-%d 00:00:00', ?, ?, 'weekday 0', '-6 days')", Vars: []interface{}{field, shift}}
+	case "month":
+		return clause.Expr{SQL: "STRFTIME('%Y-%m-01 00:00:00', ?, ?)", Vars: []interface{}{field, shift}}
+	default:
+		return clause.Expr{SQL: "STRFTIME('%Y-%m-%d 00:00:00', ?, ?)", Vars: []interface{}{field, shift}}
+	}
+}
+
+// nextBucket returns the start of the bucket following start.
+func nextBucket(start time.Time, interval string) time.Time {
+	switch interval {
+	case "hour":
+		return start.Add(time.Hour)
+	case "week":
+		return start.AddDate(0, 0, 7)
+	case "month":
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// dateHistogram counts the rows per time bucket of spec.Field, summing spec.SumFields per
+// bucket. Buckets between the first and the last one that hold no rows are reported as zeros.
+func dateHistogram(db *gorm.DB, spec SummarySpec) ([]map[string]interface{}, error) {
+	loc := spec.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	field := clause.Column{Name: spec.Field}
+	bucket := bucketExpr(db.Dialector.Name(), field, spec.Interval, loc)
+	columns, vars := []string{bucket.SQL + " AS bucket", "COUNT(*) AS count"}, bucket.Vars
+	for _, field := range spec.SumFields {
+		columns = append(columns, "SUM(?)")
+		vars = append(vars, clause.Column{Name: field})
+	}
+
+	rows, err := db.Where("? IS NOT NULL", field).
+		Clauses(clause.Select{Expression: clause.Expr{SQL: strings.Join(columns, ", "), Vars: vars}}).
+		Group("bucket").Order("bucket").
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	histogram := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		var (
+			start string
+			count int64
+			sums  = sumDests(len(spec.SumFields), spec.decimal)
+		)
+		if err := rows.Scan(append([]interface{}{&start, &count}, sums...)...); err != nil {
+			return nil, err
+		}
+
+		at, err := time.ParseInLocation(bucketLayout, start, loc)
+		if err != nil {
+			return nil, err
+		}
+
+		// Fill the buckets skipped since the previous row
+		if n := len(histogram); n > 0 {
+			previous := histogram[n-1]["bucket"].(time.Time)
+			for next := nextBucket(previous, spec.Interval); next.Before(at); next = nextBucket(next, spec.Interval) {
+				histogram = append(histogram, histogramBucket(next, 0, sumDests(len(spec.SumFields), spec.decimal), spec.SumFields))
+			}
+		}
+		histogram = append(histogram, histogramBucket(at, count, sums, spec.SumFields))
+	}
+	return histogram, rows.Err()
+}
+
+// histogramBucket builds the output entry of one histogram bucket from the scanned sums.
+func histogramBucket(start time.Time, count int64, sums []interface{}, fields []string) map[string]interface{} {
+	bucket := map[string]interface{}{"bucket": start, "count": count}
+	for i, field := range fields {
+		bucket[field+"_sum"], _ = sumValue(sums[i])
+	}
+	return bucket
+}
+
+// validateBuckets checks the edges of a range or the bucket count of a histogram.
+func (s SummarySpec) validateBuckets() error {
+	if s.Type == "histogram" {
+		if s.Buckets <= 0 {
+			return fmt.Errorf("%w: histogram needs at least one bucket", ErrInvalidSummary)
+		}
+		return nil
+	}
+
+	if len(s.Edges) == 0 {
+		return fmt.Errorf("%w: range needs at least one edge", ErrInvalidSummary)
+	}
+	if !sort.Float64sAreSorted(s.Edges) {
+		return fmt.Errorf("%w: range edges must be ascending", ErrInvalidSummary)
+	}
+	return nil
+}
+
+// numericHistogram counts the rows per numeric bucket of spec.Field, summing spec.SumFields per
+// bucket. Every bucket is reported, including empty ones.
+func numericHistogram(db *gorm.DB, spec SummarySpec) ([]map[string]interface{}, error) {
+	db = db.Session(&gorm.Session{})
+	field := clause.Column{Name: spec.Field}
+	edges, buckets := spec.Edges, len(spec.Edges)
+
+	if spec.Type == "histogram" {
+		// Derive equal-width edges from the range of the values
+		var bounds struct {
+			Low  sql.NullFloat64
+			High sql.NullFloat64
+		}
+		err := db.Clauses(clause.Select{Expression: clause.Expr{SQL: "MIN(?) AS low, MAX(?) AS high", Vars: []interface{}{field, field}}}).
+			Scan(&bounds).Error
+		if err != nil {
+			return nil, err
+		}
+		if !bounds.Low.Valid {
+			// No values to derive edges from, so no buckets
+			return make([]map[string]interface{}, 0), nil
+		}
+
+		low, high := bounds.Low.Float64, bounds.High.Float64
+		buckets = spec.Buckets
+		if high == low {
+			buckets = 1
+		}
+
+		width := (high - low) / float64(buckets)
+		edges = make([]float64, buckets+1)
+		for i := range edges {
+			edges[i] = low + width*float64(i)
+		}
+		edges[buckets] = high
+	}
+
+	// The last bucket has no upper bound: it is open-ended for a range and ends at the maximum
+	// for a histogram
+	var (
+		cases []string
+		vars  []interface{}
+	)
+	for i := 0; i < buckets; i++ {
+		if i == buckets-1 {
+			cases = append(cases, "WHEN ? >= ? THEN "+strconv.Itoa(i))
+			vars = append(vars, field, edges[i])
+		} else {
+			cases = append(cases, "WHEN ? >= ? AND ? < ? THEN "+strconv.Itoa(i))
+			vars = append(vars, field, edges[i], field, edges[i+1])
+		}
+	}
+
+	columns := []string{"CASE " + strings.Join(cases, " ") + " END AS bucket", "COUNT(*) AS count"}
+	for _, field := range spec.SumFields {
+		columns = append(columns, "SUM(?)")
+		vars = append(vars, clause.Column{Name: field})
+	}
+
+	rows, err := db.Where("? >= ?", field, edges[0]).
+		Clauses(clause.Select{Expression: clause.Expr{SQL: strings.Join(columns, ", "), Vars: vars}}).
+		Group("bucket").
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	histogram := make([]map[string]interface{}, buckets)
+	for i := range histogram {
+		histogram[i] = histogramRange(edges, i, 0, sumDests(len(spec.SumFields), spec.decimal), spec.SumFields)
+	}
+	for rows.Next() {
+		var (
+			bucket int
+			count  int64
+			sums   = sumDests(len(spec.SumFields), spec.decimal)
+		)
+		if err := rows.Scan(append([]interface{}{&bucket, &count}, sums...)...); err != nil {
+			return nil, err
+		}
+		histogram[bucket] = histogramRange(edges, bucket, count, sums, spec.SumFields)
+	}
+	return histogram, rows.Err()
+}
+
+// histogramRange builds the output entry of the i-th numeric bucket from the scanned sums.
+func histogramRange(edges []float64, i int, count int64, sums []interface{}, fields []string) map[string]interface{} {
+	bucket := map[string]interface{}{"from": edges[i], "to": nil, "count": count}
+	if i+1 < len(edges) {
+		bucket["to"] = edges[i+1]
+	}
+	for j, field := range fields {
+		bucket[field+"_sum"], _ = sumValue(sums[j])
+	}
+	return bucket
+}
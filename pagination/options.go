@@ -1,5 +1,7 @@
 package pagination
 
+import "time"
+
 // PaginatorOption is a function that configures a Paginator.
 type PaginatorOption func(*Paginator)
 
@@ -28,9 +30,130 @@ func WithSort(sort ...string) PaginatorOption {
 	}
 }
 
+// WithFilters adds filters applied to the data, count and summary queries.
+func WithFilters(filters ...Filter) PaginatorOption {
+	return func(p *Paginator) {
+		p.Filters = append(p.Filters, filters...)
+	}
+}
+
+// WithGroupBy groups the data by the given fields; TotalData then counts groups.
+func WithGroupBy(fields ...string) PaginatorOption {
+	return func(p *Paginator) {
+		p.GroupBy(fields...)
+	}
+}
+
 // WithSummaryFields sets the fields for which summaries should be calculated.
 func WithSummaryFields(fields ...string) PaginatorOption {
 	return func(p *Paginator) {
 		p.SummaryFields = fields
 	}
 }
+
+// WithSummaries adds typed summary specs, e.g. pagination.Sum("trx_amount").As("total").
+func WithSummaries(specs ...SummarySpec) PaginatorOption {
+	return func(p *Paginator) {
+		p.Summaries = append(p.Summaries, specs...)
+	}
+}
+
+// WithSummaryErrorPolicy sets whether a failing summary field fails Paginate or is reported
+// in Result.SummaryErrors.
+func WithSummaryErrorPolicy(policy SummaryErrorPolicy) PaginatorOption {
+	return func(p *Paginator) {
+		p.SummaryErrorPolicy = policy
+	}
+}
+
+// WithCursor switches the paginator to keyset pagination, resuming after the given cursor.
+// An empty cursor fetches the first page.
+func WithCursor(cursor string) PaginatorOption {
+	return func(p *Paginator) {
+		p.UseCursor = true
+		p.Cursor = cursor
+	}
+}
+
+// WithQueryTimeout bounds each query phase (data, count and summary) by the given duration.
+func WithQueryTimeout(timeout time.Duration) PaginatorOption {
+	return func(p *Paginator) {
+		if timeout > 0 {
+			p.QueryTimeout = timeout
+		}
+	}
+}
+
+// WithCountStrategy sets how the total number of rows is computed.
+func WithCountStrategy(strategy CountStrategy) PaginatorOption {
+	return func(p *Paginator) {
+		p.CountStrategy = strategy
+	}
+}
+
+// WithCappedCount stops counting after limit rows, reporting e.g. "10000+" as the total.
+func WithCappedCount(limit int64) PaginatorOption {
+	return func(p *Paginator) {
+		if limit > 0 {
+			p.CountStrategy = CountCapped
+			p.CountLimit = limit
+		}
+	}
+}
+
+// WithConcurrentQueries runs the data, count and summary queries in parallel, using at most
+// limit connections at a time.
+func WithConcurrentQueries(limit int) PaginatorOption {
+	return func(p *Paginator) {
+		if limit > 0 {
+			p.Concurrency = limit
+		}
+	}
+}
+
+// WithSummaryScope sets the rows summaries aggregate unless a summary sets its own scope:
+// SummaryScopeFiltered (the default), SummaryScopePage or SummaryScopeBoth.
+func WithSummaryScope(scope SummaryScope) PaginatorOption {
+	return func(p *Paginator) {
+		p.SummaryScope = scope
+	}
+}
+
+// WithDecimalSummaries returns the sums, minimums, maximums, averages and other non-count
+// scalar summaries, as well as percentiles and the sums of distributions and histograms, as
+// exact Decimal values instead of float64, encoded to JSON as encoding. Defaults to
+// DecimalString when encoding is empty.
+func WithDecimalSummaries(encoding DecimalEncoding) PaginatorOption {
+	return func(p *Paginator) {
+		if encoding == "" {
+			encoding = DecimalString
+		}
+		p.DecimalEncoding = encoding
+	}
+}
+
+// WithSummaryComparison compares every summary value with the same aggregation over another
+// period, ComparePrevious or CompareLastYear, derived from the DateRangeFilter among the
+// filters. The comparison is reported in Result.Comparison.
+func WithSummaryComparison(period ComparePeriod) PaginatorOption {
+	return func(p *Paginator) {
+		p.Comparison = period
+	}
+}
+
+// WithAllowedFields rejects filters and sorts on fields other than the given ones with a
+// ValidationError. Fields may be qualified by their table, e.g. "transactions.trx_date".
+func WithAllowedFields(fields ...string) PaginatorOption {
+	return func(p *Paginator) {
+		p.StrictFields = true
+		p.AllowedFields = append(p.AllowedFields, fields...)
+	}
+}
+
+// WithModelFields rejects filters and sorts on fields that are not columns of the paginated
+// model with a ValidationError. WithAllowedFields takes precedence when both are used.
+func WithModelFields() PaginatorOption {
+	return func(p *Paginator) {
+		p.StrictFields = true
+	}
+}
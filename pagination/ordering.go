@@ -1,18 +1,53 @@
 package pagination
 
-import "gorm.io/gorm"
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
 
 // Ordering defines an interface for applying orderings.
 type Ordering interface {
 	Apply(db *gorm.DB) *gorm.DB
 }
 
-// OrderBy applies a simple ordering.
+// OrderBy applies a simple ordering on a column, quoted as such.
 type OrderBy struct {
 	Field     string
 	Direction string // "asc" or "desc"
 }
 
 func (o OrderBy) Apply(db *gorm.DB) *gorm.DB {
-	return db.Order(o.Field + " " + o.Direction)
+	if err := checkDirection(o.Direction); err != nil {
+		db.AddError(err)
+		return db
+	}
+	return db.Order(clause.OrderByColumn{Column: clause.Column{Name: o.Field}, Desc: o.desc()})
+}
+
+// desc reports whether the ordering is descending.
+func (o OrderBy) desc() bool {
+	return strings.EqualFold(o.Direction, "desc")
+}
+
+// parseSort splits a sort expression such as "trx_date desc, id" into orderings. Each part
+// holds a field, optionally followed by its direction; anything else is a ValidationError.
+func parseSort(sort string) ([]OrderBy, error) {
+	var orders []OrderBy
+	for _, part := range strings.Split(sort, ",") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) > 2 {
+			return nil, &ValidationError{Kind: "sort", Value: strings.TrimSpace(part), Reason: `must be a field optionally followed by "asc" or "desc"`}
+		}
+		order := OrderBy{Field: fields[0], Direction: "asc"}
+		if len(fields) > 1 {
+			order.Direction = strings.ToLower(fields[1])
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
 }
@@ -1,40 +1,66 @@
 package pagination
 
 import (
+	"context"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
-	"strings"
+	"reflect"
+	"strconv"
+	"time"
 )
 
 // Paginator handles the pagination logic.
 type Paginator struct {
-	DB            *gorm.DB
-	Page          int
-	PageSize      int
-	Total         int64
-	Sort          []string
-	Filters       []Filter
-	Groups        []string
-	SummaryFields []string
-	Orderings     []Ordering
+	DB                 *gorm.DB
+	Page               int
+	PageSize           int
+	Total              int64
+	Sort               []string
+	Filters            []Filter
+	Groups             []string
+	SummaryFields      []string
+	Summaries          []SummarySpec
+	SummaryErrorPolicy SummaryErrorPolicy
+	SummaryScope       SummaryScope
+	DecimalEncoding    DecimalEncoding
+	Comparison         ComparePeriod
+	AllowedFields      []string
+	StrictFields       bool
+	Orderings          []Ordering
+	UseCursor          bool
+	Cursor             string
+	QueryTimeout       time.Duration
+	Concurrency        int
+	CountStrategy      CountStrategy
+	CountLimit         int64
 }
 
-// Result contains the paginated result.
+// Result contains the paginated result. CountStrategy reports the strategy that actually
+// produced TotalData, and TotalDataLabel holds its display form (e.g. "10000+") when capped.
 type Result struct {
-	Data       interface{}            `json:"data"`
-	TotalData  int64                  `json:"totalData"`
-	Page       int                    `json:"page"`
-	PageSize   int                    `json:"pageSize"`
-	TotalPages int                    `json:"totalPages"`
-	Summary    map[string]interface{} `json:"summary,omitempty"`
+	Data              interface{}            `json:"data"`
+	TotalData         int64                  `json:"totalData"`
+	TotalDataLabel    string                 `json:"totalDataLabel,omitempty"`
+	CountStrategy     CountStrategy          `json:"countStrategy"`
+	Page              int                    `json:"page"`
+	PageSize          int                    `json:"pageSize"`
+	TotalPages        int                    `json:"totalPages"`
+	HasNext           bool                   `json:"hasNext"`
+	Summary           map[string]interface{} `json:"summary,omitempty"`
+	SummaryErrors     map[string]string      `json:"summaryErrors,omitempty"`
+	PageSummary       map[string]interface{} `json:"pageSummary,omitempty"`
+	PageSummaryErrors map[string]string      `json:"pageSummaryErrors,omitempty"`
+	Comparison        *SummaryComparison     `json:"comparison,omitempty"`
+	NextCursor        string                 `json:"nextCursor,omitempty"`
+	PrevCursor        string                 `json:"prevCursor,omitempty"`
 }
 
 // NewPaginator initializes a new Paginator instance with required parameters.
 func NewPaginator(db *gorm.DB, options ...PaginatorOption) *Paginator {
 	p := &Paginator{
-		DB:       db,
-		Page:     1,
-		PageSize: 10,
+		DB:         db,
+		Page:       1,
+		PageSize:   10,
+		CountLimit: 10000,
 	}
 
 	for _, option := range options {
@@ -46,6 +72,11 @@ func NewPaginator(db *gorm.DB, options ...PaginatorOption) *Paginator {
 
 // Paginate executes the pagination and returns the result.
 func (p *Paginator) Paginate(result interface{}) (*Result, error) {
+	return p.PaginateContext(context.Background(), result)
+}
+
+// PaginateContext executes the pagination, running the data, count and summary queries with ctx.
+func (p *Paginator) PaginateContext(ctx context.Context, result interface{}) (*Result, error) {
 	if p.PageSize <= 0 {
 		return nil, ErrInvalidPageSize
 	}
@@ -54,125 +85,196 @@ func (p *Paginator) Paginate(result interface{}) (*Result, error) {
 		return nil, ErrInvalidPage
 	}
 
-	offset := (p.Page - 1) * p.PageSize
-	query := p.DB.Offset(offset).Limit(p.PageSize)
+	model := modelOf(result)
+	if err := p.validate(model); err != nil {
+		return nil, err
+	}
 
-	// Apply filters
-	for _, filter := range p.Filters {
-		query = filter.Apply(query)
+	res := &Result{
+		Data:     result,
+		Page:     p.Page,
+		PageSize: p.PageSize,
 	}
 
-	// Apply groupings
-	if len(p.Groups) > 0 {
-		groupByClause := clause.GroupBy{
-			Columns: make([]clause.Column, len(p.Groups)),
+	var (
+		counted int64
+		fetched int
+		hasMore bool
+	)
+
+	// Only an exact count tells for sure whether another page follows. A capped count cannot
+	// tell past the cap and an estimate may be stale, so they look for one extra row instead
+	extraRow := p.CountStrategy == CountNone || p.CountStrategy == CountCapped || p.CountStrategy == CountEstimated
+
+	// Fetch paginated results
+	phases := []phase{{name: "data", run: func(ctx context.Context) error {
+		query := p.query(ctx)
+		if !p.UseCursor {
+			limit := p.PageSize
+			if extraRow {
+				limit++
+			}
+			offset := (p.Page - 1) * p.PageSize
+			query = query.Offset(offset).Limit(limit)
+		}
+
+		if p.UseCursor {
+			// Seek past the cursor instead of skipping rows with an offset
+			return p.paginateCursor(query, result, res)
+		}
+
+		if err := p.order(query).Find(result).Error; err != nil {
+			return err
 		}
-		for i, group := range p.Groups {
-			groupByClause.Columns[i] = clause.Column{Name: group}
+		if extraRow {
+			var rows reflect.Value
+			rows, hasMore = trimRows(result, p.PageSize)
+			if rows.Kind() == reflect.Slice {
+				fetched = rows.Len()
+			}
 		}
-		query = query.Clauses(groupByClause)
+		return nil
+	}}}
+
+	// Fetch total count
+	if p.CountStrategy != CountNone {
+		phases = append(phases, phase{name: "count", run: func(ctx context.Context) (err error) {
+			counted, res.CountStrategy, err = p.count(ctx, model)
+			return err
+		}})
 	}
 
-	// Apply orderings
-	for _, order := range p.Orderings {
-		query = order.Apply(query)
+	// Calculate summary if requested, over all filtered rows and over the page rows
+	summary, summaryPhases, err := p.summaryPhases(p.summarySpecs(SummaryScopeFiltered), p.filteredSource(model))
+	if err != nil {
+		return nil, err
 	}
+	phases = append(phases, summaryPhases...)
 
-	// Apply sorting
-	for _, sort := range p.Sort {
-		query = query.Order(sort)
+	pageSummary, pageSummaryPhases, err := p.summaryPhases(p.summarySpecs(SummaryScopePage), p.pageSource(model))
+	if err != nil {
+		return nil, err
 	}
+	phases = append(phases, pageSummaryPhases...)
 
-	// Fetch paginated results
-	if err := query.Find(result).Error; err != nil {
+	comparison, comparedSummary, comparisonPhases, err := p.comparisonPhases(model)
+	if err != nil {
 		return nil, err
 	}
+	phases = append(phases, comparisonPhases...)
 
-	// Fetch total count
-	if err := p.DB.Model(result).Count(&p.Total).Error; err != nil {
+	if err := p.runPhases(ctx, phases...); err != nil {
 		return nil, err
 	}
+	res.Summary, res.SummaryErrors = summary.result()
+	res.PageSummary, res.PageSummaryErrors = pageSummary.result()
+	if comparison != nil {
+		comparison.compare(res.Summary, comparedSummary)
+		res.Comparison = comparison
+	}
+
+	if p.CountStrategy == CountNone {
+		res.CountStrategy = CountNone
+		if !p.UseCursor {
+			res.HasNext = hasMore
+		}
+		return res, nil
+	}
+
+	capped := res.CountStrategy == CountCapped && counted > p.CountLimit
+	inexact := capped || res.CountStrategy == CountEstimated
+	p.Total = counted
+	if capped {
+		p.Total = p.CountLimit
+		res.TotalDataLabel = strconv.FormatInt(p.CountLimit, 10) + "+"
+	}
+	if !p.UseCursor {
+		res.HasNext = int64(p.Page*p.PageSize) < counted
+		if inexact {
+			// The count stops at the cap or may be stale, so rely on the extra row
+			res.HasNext = hasMore
+		}
+	}
 
 	// Calculate TotalPages safely
 	totalPages := int(p.Total / int64(p.PageSize))
 	if p.Total%int64(p.PageSize) != 0 {
 		totalPages++
 	}
+	if inexact && !p.UseCursor {
+		// Past the cap or the estimate, report at least the pages known to exist: the current
+		// one when it has rows and the next one when the extra row was found
+		known := 0
+		if fetched > 0 {
+			known = p.Page
+		}
+		if res.HasNext {
+			known = p.Page + 1
+		}
+		if totalPages < known {
+			totalPages = known
+		}
+	}
 
 	// **Fix for edge cases with 0 data**
 	if p.Total == 0 {
 		totalPages = 1 // Ensure there is always at least one page
 	}
 
-	// Calculate summary if requested
-	summary := p.Summary(result)
+	res.TotalData = p.Total
+	res.TotalPages = totalPages
 
-	return &Result{
-		Data:       result,
-		TotalData:  p.Total,
-		Page:       p.Page,
-		PageSize:   p.PageSize,
-		TotalPages: totalPages,
-		Summary:    summary,
-	}, nil
+	return res, nil
 }
 
-// Summary calculates the summary fields dynamically.
-func (p *Paginator) Summary(model interface{}) map[string]interface{} {
-	if len(p.SummaryFields) == 0 {
-		return nil
+// filteredQuery returns a fresh query on p.DB bound to ctx with the paginator filters applied.
+// Data, count and summary queries are all built from it so they describe the same set of rows.
+func (p *Paginator) filteredQuery(ctx context.Context) *gorm.DB {
+	query := p.DB.WithContext(ctx)
+
+	// Apply filters
+	for _, filter := range p.Filters {
+		query = filter.Apply(query)
 	}
 
-	summary := make(map[string]interface{})
-	for _, field := range p.SummaryFields {
-		// Expecting field to be in format "field:aggregationType"
-		parts := strings.Split(field, ":")
-		fieldName := parts[0]
-		aggregationType := "sum" // Default to sum if not specified
-		if len(parts) > 1 {
-			aggregationType = parts[1]
-		}
+	return query
+}
 
-		switch aggregationType {
-		case "sum":
-			var sumResult float64
-			p.DB.Model(model).Select("SUM(" + fieldName + ")").Scan(&sumResult)
-			summary[fieldName+"_sum"] = sumResult
-
-		case "min":
-			var minResult float64
-			p.DB.Model(model).Select("MIN(" + fieldName + ")").Scan(&minResult)
-			summary[fieldName+"_min"] = minResult
-
-		case "max":
-			var maxResult float64
-			p.DB.Model(model).Select("MAX(" + fieldName + ")").Scan(&maxResult)
-			summary[fieldName+"_max"] = maxResult
-
-		case "distribution":
-			// Generic distribution counting based on field value
-			var distribution []map[string]interface{}
-			p.DB.Model(model).Select(fieldName + ", COUNT(*) as count").Group(fieldName).Order(fieldName).Scan(&distribution)
-			summary[fieldName+"_distribution"] = distribution
-
-		case "value_count":
-			// Dynamic counting of specific field values
-			if len(parts) > 2 {
-				// If specific values are provided, split them and count each
-				values := strings.Split(parts[2], "|") // Expecting values in format field:aggregationType:value1|value2|...
-				for _, value := range values {
-					var countResult int64
-					p.DB.Model(model).Where(fieldName+" = ?", value).Count(&countResult)
-					summary[fieldName+"_"+value+"_count"] = countResult
-				}
-			} else {
-				// If no specific value is provided, count non-NULL values (similar to "count")
-				var countResult int64
-				p.DB.Model(model).Where(fieldName + " IS NOT NULL").Count(&countResult)
-				summary[fieldName+"_count"] = countResult
-			}
+// order applies the paginator orderings and sort to query.
+func (p *Paginator) order(query *gorm.DB) *gorm.DB {
+	// Apply orderings
+	for _, order := range p.Orderings {
+		query = order.Apply(query)
+	}
+
+	// Apply sorting
+	for _, sort := range p.Sort {
+		orders, err := parseSort(sort)
+		if err != nil {
+			query.AddError(err)
+			return query
 		}
+		for _, order := range orders {
+			query = order.Apply(query)
+		}
+	}
+
+	return query
+}
+
+// dialect returns the name of the database dialect, e.g. "postgres" or "sqlite".
+func (p *Paginator) dialect() string {
+	return p.DB.Dialector.Name()
+}
+
+// query returns the filtered query with the paginator groupings applied.
+func (p *Paginator) query(ctx context.Context) *gorm.DB {
+	query := p.filteredQuery(ctx)
+
+	// Apply groupings
+	if len(p.Groups) > 0 {
+		query = query.Clauses(p.groupByClause())
 	}
 
-	return summary
+	return query
 }
@@ -0,0 +1,64 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// phase is a unit of query work, such as fetching the page, counting or one aggregation.
+type phase struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// runPhases runs the phases one after another, or in parallel on separate connections when
+// Concurrency is set. The first error cancels the phases still running.
+func (p *Paginator) runPhases(ctx context.Context, phases ...phase) error {
+	if p.Concurrency <= 0 || len(phases) < 2 {
+		for _, ph := range phases {
+			if err := p.runPhase(ctx, ph.name, ph.run); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	group, ctx := errgroup.WithContext(ctx)
+	group.SetLimit(p.Concurrency)
+	for _, ph := range phases {
+		ph := ph
+		group.Go(func() error {
+			return p.runPhase(ctx, ph.name, ph.run)
+		})
+	}
+	return group.Wait()
+}
+
+// runPhase runs one query phase, bounding it by QueryTimeout when set.
+func (p *Paginator) runPhase(ctx context.Context, phase string, run func(ctx context.Context) error) error {
+	if p.QueryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.QueryTimeout)
+		defer cancel()
+	}
+
+	err := run(ctx)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s query: %w: %w", phase, ErrQueryTimeout, err)
+	}
+	return err
+}
+
+// modelOf returns a fresh value of the same type as result, so count and summary queries can
+// infer the model without touching the destination the data query is writing to.
+func modelOf(result interface{}) interface{} {
+	t := reflect.TypeOf(result)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return result
+	}
+	return reflect.New(t.Elem()).Interface()
+}
@@ -0,0 +1,505 @@
+package pagination
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FilterSyntaxError reports where a filter query could not be parsed.
+type FilterSyntaxError struct {
+	Position int // byte offset in the query, starting at 0
+	Message  string
+}
+
+func (e *FilterSyntaxError) Error() string {
+	return "filter syntax error at position " + strconv.Itoa(e.Position) + ": " + e.Message
+}
+
+// rsqlComparisons maps the RSQL comparison operators to SQL ones. FormatFilter writes the
+// FIQL forms, such as "=ge=".
+var rsqlComparisons = map[string]string{
+	"==":   "=",
+	"!=":   "!=",
+	"=lt=": "<",
+	"<":    "<",
+	"=le=": "<=",
+	"<=":   "<=",
+	"=gt=": ">",
+	">":    ">",
+	"=ge=": ">=",
+	">=":   ">=",
+}
+
+// ParseFilter parses an RSQL (FIQL) filter query into a filter tree. ";" combines constraints
+// with AND, "," with OR (AND binds tighter), and parentheses group them:
+//
+//	trx_type==income;trx_amount=ge=100,(account_number=in=(123,456))
+//
+// Comparisons are ==, !=, =lt= (or <), =le= (<=), =gt= (>) and =ge= (>=), plus =in= and =out=
+// with a list of values. Beyond RSQL, "!(...)" negates a group and the following operators
+// map to the other built-in filters:
+//
+//	=between=(low,high)  DateRangeFilter
+//	=isnull=true|false   NullFilter
+//	=like= / =notlike=   LikeFilter
+//	=search=             SearchFilter
+//	=contains=           ContainsFilter
+//	=starts= / =ends=    StartsWithFilter / EndsWithFilter
+//
+// Values containing spaces or reserved characters are quoted with ' or ", escaping with a
+// backslash. All values are strings, which the database converts to the column type. An
+// empty query yields an empty AndFilter, matching every row. Syntax errors are reported as a
+// *FilterSyntaxError.
+func ParseFilter(query string) (Filter, error) {
+	p := &filterParser{input: query}
+	if p.skipSpace(); p.eof() {
+		return And(), nil
+	}
+
+	filter, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); !p.eof() {
+		return nil, p.errorf(p.pos, "unexpected %q", p.input[p.pos])
+	}
+	return filter, nil
+}
+
+// filterParser is a recursive descent parser over an RSQL query.
+type filterParser struct {
+	input string
+	pos   int
+}
+
+func (p *filterParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *filterParser) skipSpace() {
+	for !p.eof() && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// consume skips c if it is the next character.
+func (p *filterParser) consume(c byte) bool {
+	if !p.eof() && p.input[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) errorf(pos int, format string, args ...interface{}) error {
+	return &FilterSyntaxError{Position: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+// or parses constraints separated by ",".
+func (p *filterParser) or() (Filter, error) {
+	var filters []Filter
+	for {
+		filter, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+
+		if p.skipSpace(); !p.consume(',') {
+			break
+		}
+	}
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+	return Or(filters...), nil
+}
+
+// and parses constraints separated by ";".
+func (p *filterParser) and() (Filter, error) {
+	var filters []Filter
+	for {
+		filter, err := p.constraint()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+
+		if p.skipSpace(); !p.consume(';') {
+			break
+		}
+	}
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+	return And(filters...), nil
+}
+
+// constraint parses a comparison, a group or a negated group.
+func (p *filterParser) constraint() (Filter, error) {
+	p.skipSpace()
+	switch {
+	case p.consume('!'):
+		if !p.consume('(') {
+			return nil, p.errorf(p.pos, "expected ( after !")
+		}
+		filter, err := p.group()
+		if err != nil {
+			return nil, err
+		}
+		return Not(filter), nil
+	case p.consume('('):
+		return p.group()
+	}
+	return p.comparison()
+}
+
+// group parses the rest of a parenthesized group. "()" is an empty group.
+func (p *filterParser) group() (Filter, error) {
+	start := p.pos - 1
+	if p.skipSpace(); p.consume(')') {
+		return And(), nil
+	}
+
+	filter, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); !p.consume(')') {
+		if p.eof() {
+			return nil, p.errorf(start, "unclosed (")
+		}
+		return nil, p.errorf(p.pos, "expected ), ; or , but found %q", p.input[p.pos])
+	}
+	return filter, nil
+}
+
+// comparison parses a field, an operator and its values.
+func (p *filterParser) comparison() (Filter, error) {
+	start := p.pos
+	for !p.eof() && isFieldChar(p.input[p.pos]) {
+		p.pos++
+	}
+	field := p.input[start:p.pos]
+	if field == "" {
+		if p.eof() {
+			return nil, p.errorf(p.pos, "expected a field")
+		}
+		return nil, p.errorf(p.pos, "expected a field, found %q", p.input[p.pos])
+	}
+
+	p.skipSpace()
+	opPos := p.pos
+	op := p.operator()
+	if op == "" {
+		return nil, p.errorf(opPos, "expected an operator after %q", field)
+	}
+
+	p.skipSpace()
+	argPos := p.pos
+	var args []string
+	if p.consume('(') {
+		if p.skipSpace(); !p.consume(')') {
+			for {
+				arg, err := p.argument()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+
+				p.skipSpace()
+				if p.consume(')') {
+					break
+				}
+				if !p.consume(',') {
+					return nil, p.errorf(p.pos, "expected , or ) in the list of values")
+				}
+			}
+		}
+	} else {
+		arg, err := p.argument()
+		if err != nil {
+			return nil, err
+		}
+		args = []string{arg}
+	}
+
+	return p.comparisonFilter(field, op, opPos, args, argPos)
+}
+
+// operator parses a symbolic operator such as "==" or ">=", or a named one such as "=in=".
+func (p *filterParser) operator() string {
+	rest := p.input[p.pos:]
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(rest, op) {
+			p.pos += len(op)
+			return op
+		}
+	}
+
+	if !strings.HasPrefix(rest, "=") {
+		return ""
+	}
+	end := 1
+	for end < len(rest) && (rest[end] >= 'a' && rest[end] <= 'z' || rest[end] >= 'A' && rest[end] <= 'Z') {
+		end++
+	}
+	if end == 1 || end == len(rest) || rest[end] != '=' {
+		return ""
+	}
+	p.pos += end + 1
+	return strings.ToLower(rest[:end+1])
+}
+
+// argument parses a quoted or unquoted value.
+func (p *filterParser) argument() (string, error) {
+	p.skipSpace()
+	start := p.pos
+	if p.eof() {
+		return "", p.errorf(p.pos, "expected a value")
+	}
+
+	if quote := p.input[p.pos]; quote == '\'' || quote == '"' {
+		p.pos++
+		var value strings.Builder
+		for !p.eof() {
+			c := p.input[p.pos]
+			p.pos++
+			switch {
+			case c == quote:
+				return value.String(), nil
+			case c == '\\' && !p.eof():
+				value.WriteByte(p.input[p.pos])
+				p.pos++
+			default:
+				value.WriteByte(c)
+			}
+		}
+		return "", p.errorf(start, "unterminated quoted value")
+	}
+
+	for !p.eof() && !isReserved(p.input[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorf(p.pos, "expected a value, found %q", p.input[p.pos])
+	}
+	return p.input[start:p.pos], nil
+}
+
+// comparisonFilter builds the filter of one comparison, checking the number of its values.
+func (p *filterParser) comparisonFilter(field, op string, opPos int, args []string, argPos int) (Filter, error) {
+	single := func() (string, error) {
+		if len(args) != 1 {
+			return "", p.errorf(argPos, "%s takes a single value", op)
+		}
+		return args[0], nil
+	}
+
+	if operator, ok := rsqlComparisons[op]; ok {
+		value, err := single()
+		return ComparisonFilter{Field: field, Operator: operator, Value: value}, err
+	}
+
+	switch op {
+	case "=in=", "=out=":
+		values := make([]interface{}, len(args))
+		for i, arg := range args {
+			values[i] = arg
+		}
+		return InFilter{Field: field, Values: values, Not: op == "=out="}, nil
+	case "=between=":
+		if len(args) != 2 {
+			return nil, p.errorf(argPos, "%s takes two values", op)
+		}
+		return DateRangeFilter{Field: field, StartDate: args[0], EndDate: args[1]}, nil
+	case "=isnull=":
+		value, err := single()
+		if err != nil {
+			return nil, err
+		}
+		isNull, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, p.errorf(argPos, "%s takes true or false", op)
+		}
+		return NullFilter{Field: field, Not: !isNull}, nil
+	case "=like=", "=notlike=":
+		value, err := single()
+		return LikeFilter{Field: field, Pattern: value, Not: op == "=notlike="}, err
+	case "=search=":
+		value, err := single()
+		return SearchFilter{Field: field, Value: value}, err
+	case "=contains=":
+		value, err := single()
+		return ContainsFilter{Field: field, Value: value}, err
+	case "=starts=":
+		value, err := single()
+		return StartsWithFilter{Field: field, Value: value}, err
+	case "=ends=":
+		value, err := single()
+		return EndsWithFilter{Field: field, Value: value}, err
+	}
+	return nil, p.errorf(opPos, "unknown operator %q", op)
+}
+
+// isFieldChar reports whether c may appear in a field name, optionally qualified by its table.
+func isFieldChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.'
+}
+
+// isReserved reports whether c ends an unquoted value.
+func isReserved(c byte) bool {
+	return strings.IndexByte("\"'();,=!~<> ", c) >= 0
+}
+
+// Precedence of a formatted filter: the loosest operator at its top level.
+const (
+	precedenceOr = iota
+	precedenceAnd
+	precedenceAtom
+)
+
+// FormatFilter renders a filter tree built from the built-in filters as the RSQL query
+// ParseFilter reads. Filters without a query form yield ErrUnsupportedFilter.
+func FormatFilter(filter Filter) (string, error) {
+	query, _, err := formatFilter(filter)
+	if query == "()" {
+		// An empty tree is an empty query
+		query = ""
+	}
+	return query, err
+}
+
+// formatFilter renders filter along with its precedence.
+func formatFilter(filter Filter) (string, int, error) {
+	// Pointers to the built-in filters format like their values
+	if v := reflect.ValueOf(filter); v.Kind() == reflect.Ptr && !v.IsNil() {
+		if f, ok := v.Elem().Interface().(Filter); ok {
+			filter = f
+		}
+	}
+
+	switch f := filter.(type) {
+	case ComparisonFilter:
+		if err := checkOperator(f.Operator); err != nil {
+			return "", 0, err
+		}
+		op := "=="
+		switch f.Operator {
+		case "!=", "<>":
+			op = "!="
+		case "<":
+			op = "=lt="
+		case "<=":
+			op = "=le="
+		case ">":
+			op = "=gt="
+		case ">=":
+			op = "=ge="
+		}
+		return f.Field + op + formatValue(f.Value), precedenceAtom, nil
+	case DateRangeFilter:
+		return f.Field + "=between=" + formatValues(f.StartDate, f.EndDate), precedenceAtom, nil
+	case BetweenFilter:
+		return f.Field + "=between=" + formatValues(f.Low, f.High), precedenceAtom, nil
+	case StatusFilter:
+		values := make([]interface{}, len(f.Statuses))
+		for i, status := range f.Statuses {
+			values[i] = status
+		}
+		return f.Field + "=in=" + formatValues(values...), precedenceAtom, nil
+	case InFilter:
+		op := "=in="
+		if f.Not {
+			op = "=out="
+		}
+		return f.Field + op + formatValues(f.Values...), precedenceAtom, nil
+	case NullFilter:
+		return f.Field + "=isnull=" + strconv.FormatBool(!f.Not), precedenceAtom, nil
+	case LikeFilter:
+		op := "=like="
+		if f.Not {
+			op = "=notlike="
+		}
+		return f.Field + op + formatValue(f.Pattern), precedenceAtom, nil
+	case SearchFilter:
+		return f.Field + "=search=" + formatValue(f.Value), precedenceAtom, nil
+	case ContainsFilter:
+		return f.Field + "=contains=" + formatValue(f.Value), precedenceAtom, nil
+	case StartsWithFilter:
+		return f.Field + "=starts=" + formatValue(f.Value), precedenceAtom, nil
+	case EndsWithFilter:
+		return f.Field + "=ends=" + formatValue(f.Value), precedenceAtom, nil
+	case AndFilter:
+		return formatGroup(f.Filters, ";", precedenceAnd)
+	case OrFilter:
+		return formatGroup(f.Filters, ",", precedenceOr)
+	case NotFilter:
+		query, _, err := formatFilter(f.Filter)
+		if query == "()" {
+			return "!()", precedenceAtom, err
+		}
+		return "!(" + query + ")", precedenceAtom, err
+	case *FilterManager:
+		filters := append([]Filter(nil), f.AndFilters...)
+		if len(f.OrFilters) > 0 {
+			filters = append(filters, Or(f.OrFilters...))
+		}
+		return formatGroup(filters, ";", precedenceAnd)
+	}
+	return "", 0, fmt.Errorf("%w: %T has no filter query form", ErrUnsupportedFilter, filter)
+}
+
+// formatGroup joins the formatted filters with separator, parenthesizing those that bind
+// looser than precedence.
+func formatGroup(filters []Filter, separator string, precedence int) (string, int, error) {
+	switch len(filters) {
+	case 0:
+		return "()", precedenceAtom, nil
+	case 1:
+		return formatFilter(filters[0])
+	}
+
+	parts := make([]string, len(filters))
+	for i, filter := range filters {
+		query, prec, err := formatFilter(filter)
+		if err != nil {
+			return "", 0, err
+		}
+		if prec < precedence {
+			query = "(" + query + ")"
+		}
+		parts[i] = query
+	}
+	return strings.Join(parts, separator), precedence, nil
+}
+
+// formatValues renders a parenthesized list of values.
+func formatValues(values ...interface{}) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = formatValue(value)
+	}
+	return "(" + strings.Join(parts, ",") + ")"
+}
+
+// formatValue renders one value, quoting it when it is empty or holds reserved characters.
+func formatValue(value interface{}) string {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case time.Time:
+		s = v.Format(time.RFC3339Nano)
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	if s != "" && !strings.ContainsAny(s, "\"'();,=!~<> \\") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
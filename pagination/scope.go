@@ -0,0 +1,96 @@
+package pagination
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// SummaryScope selects the rows a summary aggregates.
+type SummaryScope string
+
+const (
+	// SummaryScopeFiltered aggregates every row matched by the filters, reported in Result.Summary.
+	SummaryScopeFiltered SummaryScope = "filtered"
+	// SummaryScopePage aggregates only the rows of the current page, reported in Result.PageSummary.
+	SummaryScopePage SummaryScope = "page"
+	// SummaryScopeBoth reports the summary in both Result.Summary and Result.PageSummary.
+	SummaryScopeBoth SummaryScope = "both"
+)
+
+// WithScope sets the rows the summary aggregates, overriding Paginator.SummaryScope.
+func (s SummarySpec) WithScope(scope SummaryScope) SummarySpec {
+	s.Scope = scope
+	return s
+}
+
+// summarySpecs returns the specs from SummaryFields followed by Summaries that aggregate the
+// rows of scope, which is either SummaryScopeFiltered or SummaryScopePage. Specs with an
+// unknown scope are returned with the filtered ones so that they are reported once.
+func (p *Paginator) summarySpecs(scope SummaryScope) []SummarySpec {
+	var all []SummarySpec
+	for _, field := range p.SummaryFields {
+		all = append(all, ParseSummaryField(field)...)
+	}
+	all = append(all, p.Summaries...)
+
+	var specs []SummarySpec
+	for _, spec := range all {
+		specScope := spec.Scope
+		if specScope == "" {
+			specScope = p.SummaryScope
+		}
+
+		switch specScope {
+		case "", SummaryScopeFiltered, SummaryScopePage:
+			if specScope == scope || (specScope == "" && scope == SummaryScopeFiltered) {
+				specs = append(specs, spec)
+			}
+		case SummaryScopeBoth:
+			specs = append(specs, spec)
+		default:
+			if scope == SummaryScopeFiltered {
+				if spec.err == nil {
+					spec.err = fmt.Errorf("%w: unknown scope %q", ErrInvalidSummary, specScope)
+				}
+				specs = append(specs, spec)
+			}
+		}
+	}
+	return specs
+}
+
+// filteredSource returns the rows matched by the paginator filters.
+func (p *Paginator) filteredSource(model interface{}) func(context.Context) *gorm.DB {
+	return func(ctx context.Context) *gorm.DB {
+		return p.filteredQuery(ctx).Model(model)
+	}
+}
+
+// pageSource returns the rows of the current page, wrapped in a subquery so that aggregations
+// see exactly the rows returned in Result.Data.
+func (p *Paginator) pageSource(model interface{}) func(context.Context) *gorm.DB {
+	return func(ctx context.Context) *gorm.DB {
+		rows, err := p.pageQuery(ctx, model)
+		query := p.newQuery(ctx).Table("(?) AS page_rows", rows)
+		if err != nil {
+			query.AddError(err)
+		}
+		return query
+	}
+}
+
+// pageQuery returns the query selecting the rows of the current page.
+func (p *Paginator) pageQuery(ctx context.Context, model interface{}) (*gorm.DB, error) {
+	query := p.query(ctx).Model(model)
+	if p.UseCursor {
+		query, _, err := p.seek(query, model)
+		if err != nil {
+			return nil, err
+		}
+		return query.Limit(p.PageSize), nil
+	}
+
+	return p.order(query).Offset((p.Page - 1) * p.PageSize).Limit(p.PageSize), nil
+}
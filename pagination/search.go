@@ -0,0 +1,395 @@
+package pagination
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// DecodeSearch reads a JSON search document with Mongo-style operators and adds its filter,
+// sort and page settings to the paginator:
+//
+//	{
+//		"trx_amount": {"$gte": 100},
+//		"$or": [{"trx_type": "income"}, {"cif": {"$like": "ABC%"}}],
+//		"$sort": ["trx_date desc"],
+//		"$page": 2,
+//		"$pageSize": 20
+//	}
+//
+// A field maps to a value, compared for equality (null meaning IS NULL), or to an object of
+// operators: $eq, $ne, $gt, $gte, $lt, $lte, $in, $nin, $between (two values), $exists,
+// $like, $notlike, $search, $contains, $starts and $ends. $and and $or take a list of
+// documents and $not a document; the conditions of a document are combined with AND.
+//
+// Values are converted to the type of the matching column of model, so "100" compares as a
+// number on a numeric column and dates parse into time.Time. The fields and sort are then
+// checked like the paginator's own, so with StrictFields set unknown fields are rejected.
+// Invalid documents yield a *ValidationError and leave the paginator unchanged.
+func (p *Paginator) DecodeSearch(data []byte, model interface{}) error {
+	var document map[string]json.RawMessage
+	if err := decodeJSON(data, &document); err != nil {
+		return err
+	}
+
+	d := &searchDecoder{}
+	if model != nil {
+		stmt := &gorm.Statement{DB: p.DB}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		d.schema = stmt.Schema
+	}
+
+	decoded := *p
+	decoded.Filters = append([]Filter(nil), p.Filters...)
+	for _, key := range []string{"$sort", "$page", "$pageSize"} {
+		raw, ok := document[key]
+		if !ok {
+			continue
+		}
+		delete(document, key)
+
+		if err := d.option(&decoded, key, raw); err != nil {
+			return err
+		}
+	}
+
+	filter, err := d.document(document)
+	if err != nil {
+		return err
+	}
+	if group, ok := filter.(AndFilter); !ok || len(group.Filters) > 0 {
+		decoded.Filters = append(decoded.Filters, filter)
+	}
+
+	if err := decoded.validate(model); err != nil {
+		return err
+	}
+	*p = decoded
+	return nil
+}
+
+// decodeJSON unmarshals data keeping numbers as json.Number, so they can be converted to the
+// column type without losing precision.
+func decodeJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return &ValidationError{Kind: "value", Value: string(data), Reason: err.Error()}
+	}
+	return nil
+}
+
+// searchDecoder turns the documents of a search into filters.
+type searchDecoder struct {
+	schema *schema.Schema
+}
+
+// option applies a "$sort", "$page" or "$pageSize" setting.
+func (d *searchDecoder) option(p *Paginator, key string, raw json.RawMessage) error {
+	if key == "$sort" {
+		var sort []string
+		if err := json.Unmarshal(raw, &sort); err != nil {
+			var single string
+			if err := json.Unmarshal(raw, &single); err != nil {
+				return &ValidationError{Kind: "value", Value: string(raw), Reason: "$sort takes a string or a list of strings"}
+			}
+			sort = []string{single}
+		}
+		p.Sort = sort
+		return nil
+	}
+
+	var n int
+	if err := json.Unmarshal(raw, &n); err != nil || n <= 0 {
+		return &ValidationError{Kind: "value", Value: string(raw), Reason: key + " takes a positive integer"}
+	}
+	if key == "$page" {
+		p.Page = n
+	} else {
+		p.PageSize = n
+	}
+	return nil
+}
+
+// document combines the conditions of a document with AND, in the order of their keys.
+func (d *searchDecoder) document(document map[string]json.RawMessage) (Filter, error) {
+	keys := make([]string, 0, len(document))
+	for key := range document {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var filters []Filter
+	for _, key := range keys {
+		filter, err := d.condition(key, document[key])
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+	return And(filters...), nil
+}
+
+// condition decodes a logical operator or the conditions on one field.
+func (d *searchDecoder) condition(key string, raw json.RawMessage) (Filter, error) {
+	switch key {
+	case "$and", "$or":
+		var documents []map[string]json.RawMessage
+		if err := decodeJSON(raw, &documents); err != nil || len(documents) == 0 {
+			return nil, &ValidationError{Kind: "value", Value: string(raw), Reason: key + " takes a non-empty list of documents"}
+		}
+
+		filters := make([]Filter, len(documents))
+		for i, document := range documents {
+			filter, err := d.document(document)
+			if err != nil {
+				return nil, err
+			}
+			filters[i] = filter
+		}
+		if key == "$or" {
+			return Or(filters...), nil
+		}
+		return And(filters...), nil
+
+	case "$not":
+		var document map[string]json.RawMessage
+		if err := decodeJSON(raw, &document); err != nil {
+			return nil, &ValidationError{Kind: "value", Value: string(raw), Reason: "$not takes a document"}
+		}
+		filter, err := d.document(document)
+		if err != nil {
+			return nil, err
+		}
+		return Not(filter), nil
+	}
+
+	if strings.HasPrefix(key, "$") {
+		return nil, &ValidationError{Kind: "operator", Value: key, Reason: "not a logical operator or a top-level setting"}
+	}
+	return d.field(key, raw)
+}
+
+// field decodes the conditions on field: a value to compare for equality, or an object of
+// operators combined with AND.
+func (d *searchDecoder) field(field string, raw json.RawMessage) (Filter, error) {
+	var operators map[string]json.RawMessage
+	if err := decodeJSON(raw, &operators); err != nil || !isOperatorObject(operators) {
+		return d.operator(field, "$eq", raw)
+	}
+
+	names := make([]string, 0, len(operators))
+	for name := range operators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	filters := make([]Filter, len(names))
+	for i, name := range names {
+		filter, err := d.operator(field, name, operators[name])
+		if err != nil {
+			return nil, err
+		}
+		filters[i] = filter
+	}
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+	return And(filters...), nil
+}
+
+// isOperatorObject reports whether every key of a field object is an operator.
+func isOperatorObject(object map[string]json.RawMessage) bool {
+	for key := range object {
+		if !strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return len(object) > 0
+}
+
+// searchComparisons maps the comparison operators of a search document to SQL ones.
+var searchComparisons = map[string]string{
+	"$eq":  "=",
+	"$ne":  "!=",
+	"$gt":  ">",
+	"$gte": ">=",
+	"$lt":  "<",
+	"$lte": "<=",
+}
+
+// operator decodes one operator applied to field.
+func (d *searchDecoder) operator(field, name string, raw json.RawMessage) (Filter, error) {
+	if operator, ok := searchComparisons[name]; ok {
+		value, err := d.value(field, raw)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case value == nil && (name == "$eq" || name == "$ne"):
+			return NullFilter{Field: field, Not: name == "$ne"}, nil
+		case value == nil:
+			return nil, &ValidationError{Kind: "value", Value: string(raw), Reason: name + " cannot compare with null"}
+		}
+		return ComparisonFilter{Field: field, Operator: operator, Value: value}, nil
+	}
+
+	switch name {
+	case "$in", "$nin":
+		values, err := d.values(field, name, raw)
+		return InFilter{Field: field, Values: values, Not: name == "$nin"}, err
+	case "$between":
+		values, err := d.values(field, name, raw)
+		if err == nil && len(values) != 2 {
+			err = &ValidationError{Kind: "value", Value: string(raw), Reason: "$between takes two values"}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return BetweenFilter{Field: field, Low: values[0], High: values[1]}, nil
+	case "$exists":
+		var exists bool
+		if err := json.Unmarshal(raw, &exists); err != nil {
+			return nil, &ValidationError{Kind: "value", Value: string(raw), Reason: "$exists takes true or false"}
+		}
+		return NullFilter{Field: field, Not: exists}, nil
+	}
+
+	build, ok := searchPatterns[name]
+	if !ok {
+		return nil, &ValidationError{Kind: "operator", Value: name, Reason: "unknown operator"}
+	}
+	var pattern string
+	if err := json.Unmarshal(raw, &pattern); err != nil {
+		return nil, &ValidationError{Kind: "value", Value: string(raw), Reason: name + " takes a string"}
+	}
+	return build(field, pattern), nil
+}
+
+// searchPatterns builds the filters of the operators matching a string pattern.
+var searchPatterns = map[string]func(field, value string) Filter{
+	"$like":     func(field, value string) Filter { return LikeFilter{Field: field, Pattern: value} },
+	"$notlike":  func(field, value string) Filter { return LikeFilter{Field: field, Pattern: value, Not: true} },
+	"$search":   func(field, value string) Filter { return SearchFilter{Field: field, Value: value} },
+	"$contains": func(field, value string) Filter { return ContainsFilter{Field: field, Value: value} },
+	"$starts":   func(field, value string) Filter { return StartsWithFilter{Field: field, Value: value} },
+	"$ends":     func(field, value string) Filter { return EndsWithFilter{Field: field, Value: value} },
+}
+
+// values decodes the list of values taken by operator.
+func (d *searchDecoder) values(field, operator string, raw json.RawMessage) ([]interface{}, error) {
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, &ValidationError{Kind: "value", Value: string(raw), Reason: operator + " takes a list of values"}
+	}
+
+	values := make([]interface{}, len(list))
+	for i, item := range list {
+		value, err := d.value(field, item)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// value decodes a scalar value, converted to the type of the column field when the model has it.
+func (d *searchDecoder) value(field string, raw json.RawMessage) (interface{}, error) {
+	var value interface{}
+	if err := decodeJSON(raw, &value); err != nil {
+		return nil, err
+	}
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return nil, &ValidationError{Kind: "value", Value: string(raw), Reason: "expected a single value for " + strconv.Quote(field)}
+	case nil:
+		return nil, nil
+	}
+
+	var column *schema.Field
+	if d.schema != nil {
+		column = lookUpField(d.schema, field)
+	}
+	if column == nil {
+		// Without a column, numbers keep their JSON form
+		if number, ok := value.(json.Number); ok {
+			if n, err := number.Int64(); err == nil {
+				return n, nil
+			}
+			return number.Float64()
+		}
+		return value, nil
+	}
+
+	converted, err := coerce(value, column.FieldType)
+	if err != nil {
+		return nil, &ValidationError{Kind: "value", Value: string(raw), Reason: err.Error() + " for " + strconv.Quote(field)}
+	}
+	return converted, nil
+}
+
+// coerce converts a decoded JSON value to t, accepting numbers and booleans written as strings.
+// Types other than strings, numbers, booleans and time.Time are left to the database.
+func coerce(value interface{}, t reflect.Type) (interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	text := fmt.Sprint(value)
+
+	if t == reflect.TypeOf(time.Time{}) {
+		for _, format := range dateLayouts {
+			if at, err := time.Parse(format.layout, text); err == nil {
+				return at, nil
+			}
+		}
+		return nil, fmt.Errorf("expected a date")
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return text, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("expected a boolean")
+		}
+		return b, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected an integer")
+		}
+		return n, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a non-negative integer")
+		}
+		return n, nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number")
+		}
+		return f, nil
+	}
+
+	if number, ok := value.(json.Number); ok {
+		return number.String(), nil
+	}
+	return value, nil
+}
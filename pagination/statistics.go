@@ -0,0 +1,214 @@
+package pagination
+
+import (
+	"database/sql"
+	"math"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// varianceScalar builds the sample variance (or standard deviation) of field. Postgres has
+// native functions; elsewhere the variance is derived from sums and the square root taken in Go.
+func varianceScalar(dialect string, field clause.Expr, stddev bool) *Scalar {
+	if dialect == "postgres" {
+		if stddev {
+			return &Scalar{SQL: "STDDEV_SAMP(?)", Vars: []interface{}{field}}
+		}
+		return &Scalar{SQL: "VAR_SAMP(?)", Vars: []interface{}{field}}
+	}
+
+	x := clause.Expr{SQL: "1.0 * ?", Vars: []interface{}{field}}
+	scalar := &Scalar{
+		SQL:  "(SUM(? * ?) - SUM(?) * SUM(?) / COUNT(?)) / (COUNT(?) - 1)",
+		Vars: []interface{}{x, field, x, x, field, field},
+	}
+	if stddev {
+		scalar.Transform = func(variance float64) float64 {
+			// Rounding can push a zero variance slightly below zero
+			return math.Sqrt(math.Max(variance, 0))
+		}
+	}
+	return scalar
+}
+
+// percentileFraction parses "median" or "pNN" aggregation types into a fraction between 0 and 1.
+func percentileFraction(aggregationType string) (float64, bool) {
+	if aggregationType == "median" {
+		return 0.5, true
+	}
+	if !strings.HasPrefix(aggregationType, "p") {
+		return 0, false
+	}
+
+	// ParseFloat also accepts forms such as "NaN", "Inf" or "1e1", so only allow digits and a dot
+	digits := aggregationType[1:]
+	if digits == "" || strings.Trim(digits, "0123456789.") != "" {
+		return 0, false
+	}
+	percent, err := strconv.ParseFloat(digits, 64)
+	if err != nil || percent < 0 || percent > 100 {
+		return 0, false
+	}
+	return percent / 100, true
+}
+
+// percentile computes the continuous percentile of field the way PERCENTILE_CONT does, for
+// dialects without it, NULL over no values. Only the count and the two values around the
+// requested rank are fetched.
+func percentile(db *gorm.DB, field string, fraction float64) (sql.NullFloat64, error) {
+	column := clause.Column{Name: field}
+	query := db.Where("? IS NOT NULL", column).Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return sql.NullFloat64{}, err
+	}
+	if total == 0 {
+		return sql.NullFloat64{}, nil
+	}
+
+	rank := fraction * float64(total-1)
+	lower := math.Floor(rank)
+
+	var values []float64
+	err := query.Clauses(clause.Select{Columns: []clause.Column{column}}).
+		Order(clause.OrderByColumn{Column: column}).
+		Offset(int(lower)).Limit(2).
+		Pluck(field, &values).Error
+	if err != nil {
+		return sql.NullFloat64{}, err
+	}
+	return interpolate(values, rank), nil
+}
+
+// interpolate returns the value at rank, a fractional position into the sorted values of which
+// values holds the one at the floor of rank and the next one, if any. NULL without values.
+func interpolate(values []float64, rank float64) sql.NullFloat64 {
+	lower := math.Floor(rank)
+	if len(values) == 0 {
+		return sql.NullFloat64{}
+	}
+	if len(values) == 1 || rank == lower {
+		return sql.NullFloat64{Float64: values[0], Valid: true}
+	}
+	return sql.NullFloat64{Float64: values[0] + (values[1]-values[0])*(rank-lower), Valid: true}
+}
+
+// percentileSubtotals computes the continuous percentile of spec.Field at every level of
+// spec.GroupBy, nested like the subtotals of scalars, for dialects without PERCENTILE_CONT.
+// Window functions rank the values within each group, so that only the values around the
+// requested rank are fetched, with one query per level.
+func percentileSubtotals(db *gorm.DB, spec SummarySpec, fraction float64) (map[string]interface{}, error) {
+	db = db.Session(&gorm.Session{})
+	field := clause.Column{Name: spec.Field}
+
+	var rows []subtotalRow
+	for level := 0; level <= len(spec.GroupBy); level++ {
+		var (
+			groups []string
+			vars   []interface{}
+		)
+		for i, group := range spec.GroupBy[:level] {
+			groups = append(groups, "g"+strconv.Itoa(i))
+			vars = append(vars, clause.Column{Name: group})
+		}
+		rowNumber, count := "ROW_NUMBER() OVER (ORDER BY ?)", "COUNT(*) OVER ()"
+		if level > 0 {
+			partition := "PARTITION BY " + strings.TrimSuffix(strings.Repeat("?, ", level), ", ")
+			rowNumber, count = "ROW_NUMBER() OVER ("+partition+" ORDER BY ?)", "COUNT(*) OVER ("+partition+")"
+		}
+
+		// Select the groups, the value, its position within its group and the group size
+		var selects []string
+		for _, group := range groups {
+			selects = append(selects, "? AS "+group)
+		}
+		selects = append(selects, "? AS v", rowNumber+" AS rn", count+" AS cnt")
+		partitionVars := vars
+		vars = append(append(append(append(vars, field), partitionVars...), field), partitionVars...)
+
+		ranked := db.Where("? IS NOT NULL", field).
+			Clauses(clause.Select{Expression: clause.Expr{SQL: strings.Join(selects, ", "), Vars: vars}})
+		query := db.Session(&gorm.Session{NewDB: true}).
+			Table("(?) AS ranked", ranked).
+			Clauses(clause.Select{Expression: clause.Expr{SQL: strings.Join(append(groups, "v", "cnt"), ", ")}}).
+			Where("rn - 1 > ? * (cnt - 1) - 1 AND rn - 1 < ? * (cnt - 1) + 1", fraction, fraction).
+			Order(strings.Join(append(groups, "rn"), ", "))
+
+		levelRows, err := percentileRows(query, level, fraction, spec.decimal)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, levelRows...)
+	}
+
+	if len(rows) == 0 {
+		// Like PERCENTILE_CONT, the percentile of no values is NULL
+		rows = []subtotalRow{{values: []interface{}{percentileValue(sql.NullFloat64{}, spec.decimal)}}}
+	}
+	return subtotalTree(spec.GroupBy, rows, 0), nil
+}
+
+// percentileRows reads the values around the rank of every group selected by query, ordered by
+// group, and interpolates the percentile of each group.
+func percentileRows(query *gorm.DB, level int, fraction float64, encoding DecimalEncoding) ([]subtotalRow, error) {
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		subtotals []subtotalRow
+		groups    []interface{}
+		values    []float64
+		count     int64
+	)
+	flush := func() {
+		if len(values) > 0 {
+			value := interpolate(values, fraction*float64(count-1))
+			subtotals = append(subtotals, subtotalRow{level: level, groups: groups, values: []interface{}{percentileValue(value, encoding)}})
+		}
+	}
+	for rows.Next() {
+		var (
+			row      = make([]interface{}, level)
+			value    float64
+			rowCount int64
+			dest     []interface{}
+		)
+		for i := range row {
+			dest = append(dest, &row[i])
+		}
+		if err := rows.Scan(append(dest, &value, &rowCount)...); err != nil {
+			return nil, err
+		}
+		for i := range row {
+			row[i] = scannedValue(row[i])
+		}
+
+		// Rows of the same group follow each other
+		if len(values) == 0 || subtotalPath(row) != subtotalPath(groups) {
+			flush()
+			groups, values, count = row, nil, rowCount
+		}
+		values = append(values, value)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return subtotals, nil
+}
+
+// percentileValue returns a percentile computed in Go as a float64 or, with encoding, as a
+// Decimal that can only be as exact as the float64.
+func percentileValue(value sql.NullFloat64, encoding DecimalEncoding) interface{} {
+	if encoding == "" {
+		return value.Float64
+	}
+	return Decimal{Value: strconv.FormatFloat(value.Float64, 'f', -1, 64), Valid: value.Valid, Encoding: encoding}
+}
@@ -0,0 +1,186 @@
+package pagination
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// subtotalRow is one row of a subtotal query: the values of the first level group fields (all
+// of them for the most detailed rows, none for the grand total) and the aggregated values.
+type subtotalRow struct {
+	level  int
+	groups []interface{}
+	values []interface{}
+}
+
+// runSubtotals calculates the scalars per group of groupBy and stores, for each of them, the
+// grand total and the nested subtotals. Like runScalars, it retries the scalars one by one when
+// the combined query fails under the report policy.
+func (p *Paginator) runSubtotals(ctx context.Context, source func(context.Context) *gorm.DB, groupBy []string, scalars []scalarAggregation, summary *summaryResult) error {
+	rows, err := p.subtotals(source(ctx), groupBy, scalars)
+	if err == nil {
+		for i, scalar := range scalars {
+			summary.store(scalar.key, subtotalTree(groupBy, rows, i))
+		}
+		return nil
+	}
+
+	if summary.policy != SummaryErrorsReport || len(scalars) == 1 {
+		return summary.fail(ctx, scalars[0].key, err)
+	}
+
+	for _, scalar := range scalars {
+		if err := p.runSubtotals(ctx, source, groupBy, []scalarAggregation{scalar}, summary); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subtotals aggregates the scalars at every level of groupBy. Postgres computes all levels with
+// GROUP BY ROLLUP; other dialects run one grouped query per level.
+func (p *Paginator) subtotals(db *gorm.DB, groupBy []string, scalars []scalarAggregation) ([]subtotalRow, error) {
+	columns, vars := scalarColumns(scalars)
+	fields := make([]clause.Column, len(groupBy))
+	for i, field := range groupBy {
+		fields[i] = clause.Column{Name: field}
+	}
+
+	// selects selects the first n group fields, followed by extra columns and the scalars
+	selects := func(n int, extra []string, extraVars []interface{}) clause.Select {
+		var (
+			sql        []string
+			selectVars []interface{}
+		)
+		for _, field := range fields[:n] {
+			sql = append(sql, "?")
+			selectVars = append(selectVars, field)
+		}
+		sql = append(append(sql, extra...), columns...)
+		selectVars = append(append(selectVars, extraVars...), vars...)
+		return clause.Select{Expression: clause.Expr{SQL: strings.Join(sql, ", "), Vars: selectVars}}
+	}
+
+	if p.dialect() == "postgres" {
+		// GROUPING(f) is 1 on the rows where f has been rolled up
+		var (
+			grouping     []string
+			groupingVars []interface{}
+			rollup       []string
+		)
+		for i, field := range fields {
+			grouping = append(grouping, "GROUPING(?) AS g"+strconv.Itoa(i))
+			groupingVars = append(groupingVars, field)
+			rollup = append(rollup, db.Statement.Quote(field))
+		}
+		query := db.Session(&gorm.Session{}).
+			Clauses(selects(len(fields), grouping, groupingVars)).
+			Group("ROLLUP(" + strings.Join(rollup, ", ") + ")")
+		for _, field := range fields {
+			query = query.Order(clause.OrderByColumn{Column: field})
+		}
+		return scanSubtotals(query, len(groupBy), len(groupBy), scalars)
+	}
+
+	var rows []subtotalRow
+	for level := 0; level <= len(groupBy); level++ {
+		query := db.Session(&gorm.Session{}).Clauses(selects(level, nil, nil))
+		if level > 0 {
+			query = query.Clauses(clause.GroupBy{Columns: fields[:level]})
+		}
+		for _, field := range fields[:level] {
+			query = query.Order(clause.OrderByColumn{Column: field})
+		}
+
+		levelRows, err := scanSubtotals(query, level, 0, scalars)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, levelRows...)
+	}
+	return rows, nil
+}
+
+// scanSubtotals reads the rows of a subtotal query selecting fields group fields, then flags
+// GROUPING flags, then the scalars. Without flags every row is at level fields.
+func scanSubtotals(query *gorm.DB, fields, flags int, scalars []scalarAggregation) ([]subtotalRow, error) {
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subtotals []subtotalRow
+	for rows.Next() {
+		var (
+			groups   = make([]interface{}, fields)
+			grouping = make([]int, flags)
+			values   = scalarDest(scalars)
+			dest     []interface{}
+		)
+		for i := range groups {
+			dest = append(dest, &groups[i])
+		}
+		for i := range grouping {
+			dest = append(dest, &grouping[i])
+		}
+		if err := rows.Scan(append(dest, values...)...); err != nil {
+			return nil, err
+		}
+
+		level := fields
+		for i, rolledUp := range grouping {
+			if rolledUp == 1 && i < level {
+				level = i
+			}
+		}
+		for i := range groups {
+			groups[i] = scannedValue(groups[i])
+		}
+		subtotals = append(subtotals, subtotalRow{level: level, groups: groups[:level], values: scalarValues(scalars, values)})
+	}
+	return subtotals, rows.Err()
+}
+
+// subtotalTree nests the subtotal rows of the i-th scalar into
+// {"total": v, "groups": [{"<field>": value, "value": v, "groups": [...]}, ...]}.
+func subtotalTree(groupBy []string, rows []subtotalRow, i int) map[string]interface{} {
+	tree := map[string]interface{}{"total": nil, "groups": []map[string]interface{}{}}
+	nodes := map[string]map[string]interface{}{"[]": tree}
+
+	// Parents are created before their children since lower levels are visited first
+	for level := 0; level <= len(groupBy); level++ {
+		for _, row := range rows {
+			if row.level != level {
+				continue
+			}
+			if level == 0 {
+				tree["total"] = row.values[i]
+				continue
+			}
+
+			parent := nodes[subtotalPath(row.groups[:level-1])]
+			if parent == nil {
+				continue
+			}
+			node := map[string]interface{}{groupBy[level-1]: row.groups[level-1], "value": row.values[i]}
+			if level < len(groupBy) {
+				node["groups"] = []map[string]interface{}{}
+			}
+			parent["groups"] = append(parent["groups"].([]map[string]interface{}), node)
+			nodes[subtotalPath(row.groups)] = node
+		}
+	}
+	return tree
+}
+
+// subtotalPath identifies a group by the values of its group fields.
+func subtotalPath(groups []interface{}) string {
+	path, _ := json.Marshal(groups)
+	return string(path)
+}
@@ -0,0 +1,401 @@
+package pagination
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// scalarAggregation is one column of the combined summary query.
+type scalarAggregation struct {
+	key       string
+	sql       string
+	vars      []interface{}
+	count     bool                  // scanned as an integer count instead of a float
+	transform func(float64) float64 // applied to the scanned value, if set
+	groupBy   []string              // subtotal fields, see SummarySpec.By
+	decimal   DecimalEncoding       // scanned as a Decimal with this encoding, if set
+}
+
+// SummaryErrorPolicy controls how Paginate reacts to a failing summary field.
+type SummaryErrorPolicy string
+
+const (
+	// SummaryErrorsFail makes Paginate return the first summary error.
+	SummaryErrorsFail SummaryErrorPolicy = "fail"
+	// SummaryErrorsReport keeps the page and reports failing fields in Result.SummaryErrors.
+	SummaryErrorsReport SummaryErrorPolicy = "report"
+)
+
+// SummaryError reports the summary fields that could not be calculated, keyed by summary key.
+type SummaryError struct {
+	Errors map[string]error
+}
+
+func (e *SummaryError) Error() string {
+	keys := make([]string, 0, len(e.Errors))
+	for key := range e.Errors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	messages := make([]string, len(keys))
+	for i, key := range keys {
+		messages[i] = key + ": " + e.Errors[key].Error()
+	}
+	return "summary failed: " + strings.Join(messages, "; ")
+}
+
+// Unwrap exposes the field errors to errors.Is and errors.As.
+func (e *SummaryError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// summaryResult collects the values and field errors produced by the summary phases.
+type summaryResult struct {
+	mu     sync.Mutex
+	policy SummaryErrorPolicy
+	values map[string]interface{}
+	errors map[string]error
+}
+
+func (r *summaryResult) store(key string, value interface{}) {
+	r.mu.Lock()
+	r.values[key] = value
+	r.mu.Unlock()
+}
+
+// fail records err for key under the report policy and returns nil, so the other fields still
+// run. Under the fail policy, or when the query was cancelled, the error is returned instead.
+func (r *summaryResult) fail(ctx context.Context, key string, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if r.policy != SummaryErrorsReport {
+		return fmt.Errorf("summary %s: %w", key, err)
+	}
+
+	r.mu.Lock()
+	r.errors[key] = err
+	r.mu.Unlock()
+	return nil
+}
+
+// result returns the summary values and the messages of the failed fields for a Result. It
+// is safe to call on a nil summaryResult.
+func (r *summaryResult) result() (map[string]interface{}, map[string]string) {
+	if r == nil {
+		return nil, nil
+	}
+
+	var errors map[string]string
+	for key, err := range r.errors {
+		if errors == nil {
+			errors = make(map[string]string)
+		}
+		errors[key] = err.Error()
+	}
+	return r.values, errors
+}
+
+// err returns the recorded field errors as a *SummaryError, or nil if every field succeeded.
+func (r *summaryResult) err() error {
+	if len(r.errors) == 0 {
+		return nil
+	}
+	return &SummaryError{Errors: r.errors}
+}
+
+// Summary calculates the summary fields dynamically. Aggregations run over the rows matched by
+// the paginator filters; groupings are not applied, so they summarize the rows behind the groups.
+//
+// Under SummaryErrorsReport the values that could be calculated are returned together with a
+// *SummaryError describing the fields that failed.
+func (p *Paginator) Summary(model interface{}) (map[string]interface{}, error) {
+	return p.SummaryContext(context.Background(), model)
+}
+
+// SummaryContext calculates the summary fields dynamically, running the aggregation queries with ctx.
+//
+// Summaries scoped to the page only are skipped, since there is no page to aggregate.
+func (p *Paginator) SummaryContext(ctx context.Context, model interface{}) (map[string]interface{}, error) {
+	if err := p.validate(model); err != nil {
+		return nil, err
+	}
+
+	summary, phases, err := p.summaryPhases(p.summarySpecs(SummaryScopeFiltered), p.filteredSource(model))
+	if err != nil || summary == nil {
+		return nil, err
+	}
+
+	if err := p.runPhases(ctx, phases...); err != nil {
+		return nil, err
+	}
+	return summary.values, summary.err()
+}
+
+// summaryPhases prepares the summary queries. Scalar aggregations (sum, min, max, avg, counts,
+// variance, net and, on Postgres, percentiles, plus any registered ScalarAggregator) are
+// combined into a single SELECT; distributions, histograms and the other aggregators need
+// their own queries. Subtotalled scalars share one grouped query per set of fields. Each phase
+// stores its values in the returned summaryResult once it has run. Every query aggregates the
+// rows selected by source.
+func (p *Paginator) summaryPhases(specs []SummarySpec, source func(context.Context) *gorm.DB) (*summaryResult, []phase, error) {
+	if len(specs) == 0 {
+		return nil, nil, nil
+	}
+
+	var (
+		summary = &summaryResult{
+			policy: p.SummaryErrorPolicy,
+			values: make(map[string]interface{}),
+			errors: make(map[string]error),
+		}
+		scalars []scalarAggregation
+		phases  []phase
+	)
+
+	// reject fails the summary for an invalid spec, or records the error under the report policy
+	reject := func(key string, err error) error {
+		if p.SummaryErrorPolicy != SummaryErrorsReport {
+			return fmt.Errorf("summary %s: %w", key, err)
+		}
+		summary.errors[key] = err
+		return nil
+	}
+
+	for _, spec := range specs {
+		spec := spec
+		key := spec.key()
+		if spec.err != nil {
+			if err := reject(key, spec.err); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		aggregator, ok := lookUpAggregator(spec.Type)
+		if !ok {
+			if err := reject(key, fmt.Errorf("%w %q", ErrUnknownAggregation, spec.Type)); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		// Scalars join the combined query
+		scalar, err := p.scalar(aggregator, key, spec)
+		if err != nil {
+			if err := reject(key, err); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		if scalar != nil {
+			scalars = append(scalars, *scalar)
+			continue
+		}
+
+		if _, percentile := aggregator.(percentileAggregator); len(spec.GroupBy) > 0 && !percentile {
+			if err := reject(key, fmt.Errorf("%w: %s cannot be subtotalled", ErrInvalidSummary, spec.Type)); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		// Other aggregations run their own queries, which the conditions simply restrict
+		source, conditions := source, spec.Conditions
+		if len(conditions) > 0 {
+			unconditional := source
+			source = func(ctx context.Context) *gorm.DB {
+				db := unconditional(ctx)
+				for _, filter := range conditions {
+					db = filter.Apply(db)
+				}
+				return db
+			}
+		}
+
+		spec.decimal = p.DecimalEncoding
+		phases = append(phases, phase{name: "summary", run: func(ctx context.Context) error {
+			value, err := aggregator.Aggregate(source(ctx), spec)
+			if err != nil {
+				return summary.fail(ctx, key, err)
+			}
+
+			summary.store(key, value)
+			return nil
+		}})
+	}
+
+	// All scalar aggregations share one round trip, plus one per set of subtotal fields
+	var (
+		scalarPhases []phase
+		grouped      = make(map[string][]scalarAggregation)
+		groupings    [][]string
+	)
+	for _, scalar := range scalars {
+		if !scalar.count {
+			scalar.decimal = p.DecimalEncoding
+		}
+
+		id := strings.Join(scalar.groupBy, ",")
+		if _, ok := grouped[id]; !ok {
+			groupings = append(groupings, scalar.groupBy)
+		}
+		grouped[id] = append(grouped[id], scalar)
+	}
+	for _, groupBy := range groupings {
+		groupBy, scalars := groupBy, grouped[strings.Join(groupBy, ",")]
+		scalarPhases = append(scalarPhases, phase{name: "summary", run: func(ctx context.Context) error {
+			if len(groupBy) > 0 {
+				return p.runSubtotals(ctx, source, groupBy, scalars, summary)
+			}
+			return p.runScalars(ctx, source, scalars, summary)
+		}})
+	}
+	phases = append(scalarPhases, phases...)
+
+	return summary, phases, nil
+}
+
+// scalar returns the column of spec in the combined summary query, or nil when its aggregator
+// needs a query of its own.
+func (p *Paginator) scalar(aggregator Aggregator, key string, spec SummarySpec) (*scalarAggregation, error) {
+	scalarAggregator, ok := aggregator.(ScalarAggregator)
+	if !ok {
+		return nil, nil
+	}
+
+	db := p.newQuery(context.Background())
+	scalar, err := scalarAggregator.Scalar(db, spec)
+	if err != nil || scalar == nil {
+		return nil, err
+	}
+
+	aggregation := scalar.aggregation(key)
+	if len(spec.Conditions) > 0 && !scalar.Filtered {
+		if p.dialect() != "postgres" {
+			// Run it on its own query, which the conditions restrict
+			return nil, nil
+		}
+
+		cond, err := condition(db, spec.Conditions)
+		if err != nil {
+			return nil, err
+		}
+		aggregation = conditional(aggregation, cond)
+	}
+
+	aggregation.groupBy = spec.GroupBy
+	return &aggregation, nil
+}
+
+// runScalars runs the scalar aggregations in one query. When that query fails under the report
+// policy, each aggregation is retried on its own to find out which fields are at fault.
+func (p *Paginator) runScalars(ctx context.Context, source func(context.Context) *gorm.DB, scalars []scalarAggregation, summary *summaryResult) error {
+	values, err := scanScalars(source(ctx), scalars)
+	if err == nil {
+		for i, scalar := range scalars {
+			summary.store(scalar.key, values[i])
+		}
+		return nil
+	}
+
+	if summary.policy != SummaryErrorsReport || len(scalars) == 1 {
+		return summary.fail(ctx, scalars[0].key, err)
+	}
+
+	for _, scalar := range scalars {
+		if err := p.runScalars(ctx, source, []scalarAggregation{scalar}, summary); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanScalars runs the scalar aggregations as one SELECT with aliased columns. NULL results,
+// such as a SUM over no rows, are reported as zero unless they are scanned as a Decimal.
+func scanScalars(db *gorm.DB, scalars []scalarAggregation) ([]interface{}, error) {
+	columns, vars := scalarColumns(scalars)
+	rows, err := db.Clauses(clause.Select{Expression: clause.Expr{SQL: strings.Join(columns, ", "), Vars: vars}}).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dest := scalarDest(scalars)
+	if rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return scalarValues(scalars, dest), nil
+}
+
+// scalarColumns returns the aliased select columns of scalars and their bound variables.
+func scalarColumns(scalars []scalarAggregation) ([]string, []interface{}) {
+	var (
+		columns []string
+		vars    []interface{}
+	)
+	for i, scalar := range scalars {
+		columns = append(columns, scalar.sql+" AS s"+strconv.Itoa(i))
+		vars = append(vars, scalar.vars...)
+	}
+	return columns, vars
+}
+
+// scalarDest returns the scan destinations for the columns of scalars.
+func scalarDest(scalars []scalarAggregation) []interface{} {
+	dest := make([]interface{}, len(scalars))
+	for i, scalar := range scalars {
+		switch {
+		case scalar.count:
+			dest[i] = &sql.NullInt64{}
+		case scalar.decimal != "" && scalar.transform == nil:
+			dest[i] = &Decimal{Encoding: scalar.decimal}
+		default:
+			dest[i] = &sql.NullFloat64{}
+		}
+	}
+	return dest
+}
+
+// scalarValues converts scanned scalar columns into summary values: int64 counts, and float64
+// or Decimal values for the other aggregations.
+func scalarValues(scalars []scalarAggregation, dest []interface{}) []interface{} {
+	values := make([]interface{}, len(scalars))
+	for i := range dest {
+		switch d := dest[i].(type) {
+		case *sql.NullInt64:
+			values[i] = d.Int64
+		case *Decimal:
+			values[i] = *d
+		case *sql.NullFloat64:
+			if transform := scalars[i].transform; transform != nil {
+				d.Float64 = transform(d.Float64)
+			}
+			values[i] = d.Float64
+
+			// Values computed in Go, such as a standard deviation, can only be as exact as a float64
+			if encoding := scalars[i].decimal; encoding != "" {
+				values[i] = Decimal{Value: strconv.FormatFloat(d.Float64, 'f', -1, 64), Valid: d.Valid, Encoding: encoding}
+			}
+		}
+	}
+	return values
+}
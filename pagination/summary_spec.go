@@ -0,0 +1,334 @@
+package pagination
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SummarySpec describes one summary aggregation. Specs are usually built with helpers such as
+// Sum, Avg, CountWhere, Percentile and Distribution, or parsed from the "field:type:values"
+// string form.
+type SummarySpec struct {
+	Field      string
+	Type       string
+	Key        string         // output key, defaults to "<field>_<type>"
+	Value      interface{}    // compared value for "value_count"
+	Interval   string         // bucket size for "date_histogram": hour, day, week or month
+	Location   *time.Location // time zone of "date_histogram" buckets, UTC when nil
+	Edges      []float64      // bucket edges for "range"; the last bucket is open-ended
+	Buckets    int            // number of equal-width buckets for "histogram"
+	Limit      int            // number of distribution buckets kept, the rest is reported as "other"
+	RankBy     string         // field whose sum ranks distribution buckets instead of the count
+	Order      string         // sort order of distribution buckets, "asc" or "desc"
+	GroupBy    []string       // fields a scalar aggregation is subtotalled by
+	Scope      SummaryScope   // rows aggregated, defaults to Paginator.SummaryScope
+	Conditions []Filter       // only rows matching every filter are aggregated, see Where
+	Inflow     []Filter       // rows added by "net"
+	Outflow    []Filter       // rows subtracted by "net"
+	Args       []string       // arguments of a registered aggregator, see RegisterAggregator
+	SumFields  []string       // fields summed per bucket by histograms
+
+	err     error           // set when the string form could not be parsed
+	decimal DecimalEncoding // encoding of exact values computed by built-in aggregators, if set
+}
+
+// Sum aggregates the sum of field.
+func Sum(field string) SummarySpec {
+	return SummarySpec{Field: field, Type: "sum"}
+}
+
+// Min aggregates the minimum of field.
+func Min(field string) SummarySpec {
+	return SummarySpec{Field: field, Type: "min"}
+}
+
+// Max aggregates the maximum of field.
+func Max(field string) SummarySpec {
+	return SummarySpec{Field: field, Type: "max"}
+}
+
+// Count counts the rows where field is not NULL.
+func Count(field string) SummarySpec {
+	return SummarySpec{Field: field, Type: "count"}
+}
+
+// CountWhere counts the rows where field equals value.
+func CountWhere(field string, value interface{}) SummarySpec {
+	return SummarySpec{Field: field, Type: "value_count", Value: value}
+}
+
+// Avg aggregates the average of field.
+func Avg(field string) SummarySpec {
+	return SummarySpec{Field: field, Type: "avg"}
+}
+
+// CountDistinct counts the distinct non-NULL values of field.
+func CountDistinct(field string) SummarySpec {
+	return SummarySpec{Field: field, Type: "count_distinct"}
+}
+
+// StdDev aggregates the sample standard deviation of field.
+func StdDev(field string) SummarySpec {
+	return SummarySpec{Field: field, Type: "stddev"}
+}
+
+// Variance aggregates the sample variance of field.
+func Variance(field string) SummarySpec {
+	return SummarySpec{Field: field, Type: "variance"}
+}
+
+// Median aggregates the median of field.
+func Median(field string) SummarySpec {
+	return SummarySpec{Field: field, Type: "median"}
+}
+
+// Percentile aggregates the continuous percentile of field, e.g. Percentile("trx_amount", 90)
+// reported as "trx_amount_p90".
+func Percentile(field string, percent float64) SummarySpec {
+	return SummarySpec{Field: field, Type: "p" + strconv.FormatFloat(percent, 'f', -1, 64)}
+}
+
+// Distribution counts the rows per distinct value of field.
+func Distribution(field string) SummarySpec {
+	return SummarySpec{Field: field, Type: "distribution"}
+}
+
+// DateHistogram counts the rows per hour, day, week or month of the timestamp field. Weeks
+// start on Monday.
+func DateHistogram(field, interval string) SummarySpec {
+	return SummarySpec{Field: field, Type: "date_histogram", Interval: interval}
+}
+
+// Aggregate summarizes field with the aggregator registered as name, passing args on in
+// SummarySpec.Args.
+func Aggregate(field, name string, args ...string) SummarySpec {
+	return SummarySpec{Field: field, Type: name, Args: args}
+}
+
+// Range counts the rows per numeric range of field. Edges 0, 100 and 1000 give the buckets
+// [0, 100), [100, 1000) and [1000, +inf); values below the first edge are not counted.
+func Range(field string, edges ...float64) SummarySpec {
+	return SummarySpec{Field: field, Type: "range", Edges: edges}
+}
+
+// Histogram counts the rows in the given number of equal-width buckets between the minimum and
+// the maximum of field.
+func Histogram(field string, buckets int) SummarySpec {
+	return SummarySpec{Field: field, Type: "histogram", Buckets: buckets}
+}
+
+// By subtotals a scalar aggregation by fields. The result holds the grand total and the
+// subtotal of every group, with each further field nested inside the previous one.
+func (s SummarySpec) By(fields ...string) SummarySpec {
+	s.GroupBy = append(append([]string(nil), s.GroupBy...), fields...)
+	return s
+}
+
+// In sets the time zone histogram buckets are computed in.
+func (s SummarySpec) In(loc *time.Location) SummarySpec {
+	s.Location = loc
+	return s
+}
+
+// WithSum adds fields summed per histogram bucket, reported as "<field>_sum".
+func (s SummarySpec) WithSum(fields ...string) SummarySpec {
+	s.SumFields = append(append([]string(nil), s.SumFields...), fields...)
+	return s
+}
+
+// As sets the key the aggregation is reported under.
+func (s SummarySpec) As(key string) SummarySpec {
+	s.Key = key
+	return s
+}
+
+// key returns the output key of the aggregation.
+func (s SummarySpec) key() string {
+	if s.Key != "" {
+		return s.Key
+	}
+
+	key := s.Field + "_" + s.Type
+	if s.Type == "value_count" {
+		key = s.Field + "_" + fmt.Sprint(s.Value) + "_count"
+	}
+	if len(s.GroupBy) > 0 {
+		key += "_by_" + strings.Join(s.GroupBy, "_")
+	}
+	return key
+}
+
+// ParseSummaryField parses the string form "field:type:args" into summary specs. The type
+// defaults to "sum". The arguments depend on the type:
+//
+//   - Any aggregation accepts "scope=page", "scope=filtered" or "scope=both", "as=" with the
+//     output key and "where=" conditions such as "trx_type=income", e.g.
+//     "trx_amount:sum:where=trx_type=income|as=income".
+//   - "net" takes the inflow and the outflow condition, e.g.
+//     "trx_amount:net:trx_type=income|trx_type=expense".
+//   - Registered aggregators receive their "|" separated arguments in SummarySpec.Args.
+//   - Scalar aggregations accept "by=" with comma separated subtotal fields, e.g.
+//     "trx_amount:sum:by=trx_type".
+//   - "value_count" takes "|" separated values and yields one spec per value, or counts non-NULL
+//     values when none are given, e.g. "trx_type:value_count:income|expense". With several
+//     values, "as=" names each count "<key>_<value>".
+//   - "date_histogram" takes the interval and optional "tz=" and "sum=" settings, e.g.
+//     "trx_date:date_histogram:day|tz=Asia/Jakarta|sum=trx_amount".
+//   - "range" takes the bucket edges, e.g. "trx_amount:range:0|100|1000", and "histogram" the
+//     number of buckets, e.g. "trx_amount:histogram:10". Both accept "sum=" as well.
+//   - "distribution" takes optional "limit=", "by=" and "order=" settings, e.g.
+//     "account_number:distribution:limit=10|by=trx_amount|order=desc".
+func ParseSummaryField(field string) []SummarySpec {
+	parts := strings.SplitN(field, ":", 3)
+	spec := SummarySpec{Field: parts[0], Type: "sum"} // Default to sum if not specified
+	if len(parts) > 1 {
+		spec.Type = parts[1]
+	}
+
+	var args []string
+	if len(parts) > 2 {
+		args = strings.Split(parts[2], "|")
+	}
+
+	// Settings shared by every type. For distributions "by=" names the ranking field instead of
+	// subtotal fields
+	var rest []string
+	for _, arg := range args {
+		if scope, ok := strings.CutPrefix(arg, "scope="); ok {
+			spec.Scope = SummaryScope(scope)
+			continue
+		}
+		if key, ok := strings.CutPrefix(arg, "as="); ok {
+			spec.Key = key
+			continue
+		}
+		if condition, ok := strings.CutPrefix(arg, "where="); ok {
+			spec.parseCondition(&spec.Conditions, condition)
+			continue
+		}
+		if fields, ok := strings.CutPrefix(arg, "by="); ok && spec.Type != "distribution" {
+			spec.GroupBy = append(spec.GroupBy, strings.Split(fields, ",")...)
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	args = rest
+
+	switch spec.Type {
+	case "value_count":
+		// The derived counts keep the shared settings. With several values, each one suffixes
+		// the "as=" key
+		shared := func(s SummarySpec, suffix string) SummarySpec {
+			s.GroupBy, s.Scope, s.Conditions = spec.GroupBy, spec.Scope, spec.Conditions
+			if spec.Key != "" {
+				s.Key = spec.Key + suffix
+			}
+			return s
+		}
+		if len(args) == 0 {
+			// If no specific value is provided, count non-NULL values
+			return []SummarySpec{shared(Count(spec.Field), "")}
+		}
+
+		var specs []SummarySpec
+		for _, value := range args {
+			suffix := ""
+			if len(args) > 1 {
+				suffix = "_" + value
+			}
+			specs = append(specs, shared(CountWhere(spec.Field, value), suffix))
+		}
+		return specs
+
+	case "net":
+		// The first condition selects the inflow and the second one the outflow
+		if len(args) != 2 {
+			spec.err = fmt.Errorf("%w: net needs an inflow and an outflow condition", ErrInvalidSummary)
+			break
+		}
+		spec.parseCondition(&spec.Inflow, args[0])
+		spec.parseCondition(&spec.Outflow, args[1])
+
+	case "date_histogram", "range", "histogram", "distribution":
+		for _, arg := range args {
+			name, value, named := strings.Cut(arg, "=")
+			if !named {
+				name, value = "", arg
+			}
+
+			switch name {
+			case "":
+				spec.parseArg(value)
+			case "interval":
+				spec.Interval = value
+			case "tz":
+				loc, err := time.LoadLocation(value)
+				if err != nil {
+					spec.err = err
+				}
+				spec.Location = loc
+			case "sum":
+				spec.SumFields = append(spec.SumFields, strings.Split(value, ",")...)
+			case "limit":
+				spec.parseLimit(value)
+			case "by":
+				spec.RankBy = value
+			case "order":
+				spec.Order = value
+			default:
+				spec.err = fmt.Errorf("%w: unknown argument %q", ErrInvalidSummary, name)
+			}
+		}
+
+	default:
+		spec.Args = args
+	}
+
+	return []SummarySpec{spec}
+}
+
+// parseArg applies a positional histogram argument: the interval of a date histogram, an edge
+// of a range or the bucket count of a histogram.
+func (s *SummarySpec) parseArg(arg string) {
+	switch s.Type {
+	case "date_histogram":
+		s.Interval = arg
+	case "range":
+		edge, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			s.err = fmt.Errorf("%w: invalid range edge %q", ErrInvalidSummary, arg)
+		}
+		s.Edges = append(s.Edges, edge)
+	case "histogram":
+		buckets, err := strconv.Atoi(arg)
+		if err != nil {
+			s.err = fmt.Errorf("%w: invalid bucket count %q", ErrInvalidSummary, arg)
+		}
+		s.Buckets = buckets
+	case "distribution":
+		s.parseLimit(arg)
+	default:
+		s.err = fmt.Errorf("%w: unexpected argument %q", ErrInvalidSummary, arg)
+	}
+}
+
+// parseCondition adds the parsed condition to filters.
+func (s *SummarySpec) parseCondition(filters *[]Filter, condition string) {
+	filter, err := parseCondition(condition)
+	if err != nil {
+		s.err = err
+		return
+	}
+	*filters = append(*filters, filter)
+}
+
+// parseLimit sets the number of buckets kept by a distribution.
+func (s *SummarySpec) parseLimit(arg string) {
+	limit, err := strconv.Atoi(arg)
+	if err != nil {
+		s.err = fmt.Errorf("%w: invalid limit %q", ErrInvalidSummary, arg)
+	}
+	s.Limit = limit
+}
@@ -0,0 +1,119 @@
+package pagination_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/xans-me/gorm-pagination/pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"testing"
+)
+
+func init() {
+	// Net flow of two transaction types, e.g. "trx_amount:net_flow:income|expense"
+	pagination.RegisterAggregator("net_flow", pagination.AggregatorFunc(func(db *gorm.DB, spec pagination.SummarySpec) (interface{}, error) {
+		var totals struct {
+			Inflow  float64
+			Outflow float64
+		}
+		field := clause.Column{Name: spec.Field}
+		err := db.Select(
+			"COALESCE(SUM(CASE WHEN trx_type = ? THEN ? END), 0) AS inflow, "+
+				"COALESCE(SUM(CASE WHEN trx_type = ? THEN ? END), 0) AS outflow",
+			spec.Args[0], field, spec.Args[1], field,
+		).Scan(&totals).Error
+		return totals.Inflow - totals.Outflow, err
+	}))
+
+	pagination.RegisterAggregator("double_sum", pagination.ScalarFunc(func(_ *gorm.DB, spec pagination.SummarySpec) (*pagination.Scalar, error) {
+		return &pagination.Scalar{SQL: "2 * SUM(?)", Vars: []interface{}{clause.Column{Name: spec.Field}}}, nil
+	}))
+
+	pagination.RegisterAggregator("row_count", pagination.ScalarFunc(func(_ *gorm.DB, spec pagination.SummarySpec) (*pagination.Scalar, error) {
+		return &pagination.Scalar{SQL: "COUNT(*)", Count: true}, nil
+	}))
+
+	pagination.RegisterAggregator("quoted_sum", pagination.ScalarFunc(func(_ *gorm.DB, spec pagination.SummarySpec) (*pagination.Scalar, error) {
+		return &pagination.Scalar{SQL: "SUM(?)", Vars: []interface{}{clause.Column{Name: spec.Field}}}, nil
+	}))
+}
+
+func TestPaginator_CustomAggregator(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithFilters(pagination.ComparisonFilter{Field: "id", Operator: "<=", Value: 2}),
+		pagination.WithSummaryFields("trx_amount:net_flow:income|expense"),
+		pagination.WithSummaries(pagination.Aggregate("trx_amount", "net_flow", "expense", "income").As("reverse_flow")),
+	)
+
+	summary, err := paginator.Summary(&[]TestData{})
+	assert.Nil(t, err)
+
+	// The filters apply to custom aggregators as well
+	assert.Equal(t, float64(-100), summary["trx_amount_net_flow"])
+	assert.Equal(t, float64(100), summary["reverse_flow"])
+}
+
+func TestPaginator_CustomScalarAggregator(t *testing.T) {
+	db := setupTestDB()
+	queries := countQueries(db)
+
+	income := pagination.ComparisonFilter{Field: "trx_type", Operator: "=", Value: "income"}
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithSummaryFields("trx_amount:sum", "trx_amount:double_sum"),
+		pagination.WithSummaries(pagination.Aggregate("trx_amount", "double_sum").Where(income).As("double_income")),
+	)
+
+	summary, err := paginator.Summary(&[]TestData{})
+	assert.Nil(t, err)
+
+	// Scalar aggregators join the combined query; conditional ones run on their own outside of Postgres
+	assert.Equal(t, 2, *queries)
+	assert.Equal(t, float64(1200), summary["trx_amount_double_sum"])
+	assert.Equal(t, float64(800), summary["double_income"])
+
+	paginator = pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithSummaries(pagination.Aggregate("trx_amount", "double_sum").By("trx_type")),
+	)
+	summary, err = paginator.Summary(&[]TestData{})
+	assert.Nil(t, err)
+	assert.Equal(t, float64(1200), summary["trx_amount_double_sum_by_trx_type"].(map[string]interface{})["total"])
+}
+
+func TestPaginator_ConditionalCustomScalar(t *testing.T) {
+	db := setupTestDB()
+	income := pagination.ComparisonFilter{Field: "trx_type", Operator: "=", Value: "income"}
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithSummaryFields("trx_amount:sum"),
+		pagination.WithSummaries(
+			pagination.Aggregate("id", "row_count").Where(income).As("income_rows"),
+			pagination.Aggregate("trx_amount", "quoted_sum").Where(income).As("income_sum"),
+			pagination.Aggregate("trx_amount", "sum").Where(income).As("builtin_income_sum"),
+		),
+	)
+
+	summary, err := paginator.Summary(&[]TestData{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), summary["income_rows"])
+	assert.Equal(t, float64(400), summary["income_sum"])
+	assert.Equal(t, float64(400), summary["builtin_income_sum"])
+	assert.Equal(t, float64(600), summary["trx_amount_sum"])
+}
+
+func TestPaginator_UnregisteredAggregator(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithSummaries(pagination.Aggregate("trx_amount", "not_registered")),
+	)
+
+	_, err := paginator.Summary(&[]TestData{})
+	assert.ErrorIs(t, err, pagination.ErrUnknownAggregation)
+}
@@ -0,0 +1,147 @@
+package pagination_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/xans-me/gorm-pagination/pagination"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"testing"
+)
+
+type CompareTestData struct {
+	ID        int
+	TrxDate   string
+	TrxAmount float64
+}
+
+// Setup test database with test data for period comparison tests
+func setupCompareTestDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db.AutoMigrate(&CompareTestData{})
+
+	db.Create(&CompareTestData{ID: 1, TrxDate: "2023-03-05", TrxAmount: 50})
+	db.Create(&CompareTestData{ID: 2, TrxDate: "2024-02-25", TrxAmount: 100})
+	db.Create(&CompareTestData{ID: 3, TrxDate: "2024-02-28", TrxAmount: 300})
+	db.Create(&CompareTestData{ID: 4, TrxDate: "2024-03-01", TrxAmount: 200})
+	db.Create(&CompareTestData{ID: 5, TrxDate: "2024-03-05", TrxAmount: 400})
+
+	return db
+}
+
+func TestPaginator_CompareWithPreviousPeriod(t *testing.T) {
+	db := setupCompareTestDB()
+
+	filters := pagination.FilterManager{}
+	filters.AddAndFilter(pagination.DateRangeFilter{Field: "trx_date", StartDate: "2024-03-01", EndDate: "2024-03-07"})
+
+	paginator := pagination.NewPaginator(
+		db.Model(&CompareTestData{}),
+		pagination.WithFilters(&filters),
+		pagination.WithSummaryFields("trx_amount:sum", "id:count", "trx_amount:histogram:1"),
+		pagination.WithSummaryComparison(pagination.ComparePrevious),
+	)
+
+	var results []CompareTestData
+	res, err := paginator.Paginate(&results)
+	assert.Nil(t, err)
+
+	// The previous seven days run from February 23 to February 29
+	assert.Equal(t, "2024-02-23", res.Comparison.StartDate)
+	assert.Equal(t, "2024-02-29", res.Comparison.EndDate)
+
+	percent := 50.0
+	assert.Equal(t, pagination.ComparedValue{Previous: float64(400), Change: float64(200), PercentChange: &percent}, res.Comparison.Values["trx_amount_sum"])
+	assert.Equal(t, int64(0), res.Comparison.Values["id_count"].Change)
+	assert.Nil(t, res.Comparison.Values["trx_amount_histogram"].Change)
+	assert.NotNil(t, res.Comparison.Values["trx_amount_histogram"].Previous)
+}
+
+func TestPaginator_CompareWithLastYear(t *testing.T) {
+	db := setupCompareTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&CompareTestData{}),
+		pagination.WithFilters(pagination.DateRangeFilter{Field: "trx_date", StartDate: "2024-03-01", EndDate: "2024-03-07"}),
+		pagination.WithDecimalSummaries(pagination.DecimalNumber),
+		pagination.WithSummaryFields("trx_amount:sum"),
+		pagination.WithSummaryComparison(pagination.CompareLastYear),
+	)
+
+	var results []CompareTestData
+	res, err := paginator.Paginate(&results)
+	assert.Nil(t, err)
+
+	data, err := json.Marshal(res.Comparison)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"period": "year",
+		"startDate": "2023-03-01",
+		"endDate": "2023-03-07",
+		"values": {"trx_amount_sum": {"previous": 50, "change": 550, "percentChange": 1100}}
+	}`, string(data))
+}
+
+func TestPaginator_CompareWithoutDateRange(t *testing.T) {
+	db := setupCompareTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&CompareTestData{}),
+		pagination.WithSummaryFields("trx_amount:sum"),
+		pagination.WithSummaryComparison(pagination.ComparePrevious),
+	)
+
+	var results []CompareTestData
+	res, err := paginator.Paginate(&results)
+	assert.Nil(t, err)
+	assert.Nil(t, res.Comparison)
+
+	paginator.Filters = []pagination.Filter{pagination.DateRangeFilter{Field: "trx_date", StartDate: "March", EndDate: "April"}}
+	_, err = paginator.Paginate(&results)
+	assert.ErrorIs(t, err, pagination.ErrInvalidSummary)
+}
+
+func TestPaginator_CompareFilterTrees(t *testing.T) {
+	db := setupCompareTestDB()
+
+	parsed, err := pagination.ParseFilter("trx_date=between=(2024-03-01,2024-03-07);trx_amount=gt=0")
+	assert.Nil(t, err)
+
+	searched := pagination.NewPaginator(db.Model(&CompareTestData{}))
+	err = searched.DecodeSearch([]byte(`{"$and": [
+		{"trx_date": {"$between": ["2024-03-01", "2024-03-07"]}},
+		{"$or": [{"trx_amount": {"$gt": 0}}, {"id": 0}]}
+	]}`), &CompareTestData{})
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name    string
+		filters []pagination.Filter
+	}{
+		{"and", []pagination.Filter{pagination.And(
+			pagination.DateRangeFilter{Field: "trx_date", StartDate: "2024-03-01", EndDate: "2024-03-07"},
+			pagination.ComparisonFilter{Field: "trx_amount", Operator: ">", Value: 0},
+		)}},
+		{"rsql", []pagination.Filter{parsed}},
+		{"search", searched.Filters},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paginator := pagination.NewPaginator(
+				db.Model(&CompareTestData{}),
+				pagination.WithFilters(tt.filters...),
+				pagination.WithSummaryFields("trx_amount:sum"),
+				pagination.WithSummaryComparison(pagination.ComparePrevious),
+			)
+
+			var results []CompareTestData
+			res, err := paginator.Paginate(&results)
+			assert.Nil(t, err)
+			if assert.NotNil(t, res.Comparison) {
+				assert.Equal(t, "2024-02-23", res.Comparison.StartDate)
+				assert.Equal(t, "2024-02-29", res.Comparison.EndDate)
+				assert.Equal(t, float64(400), res.Comparison.Values["trx_amount_sum"].Previous)
+			}
+		})
+	}
+}
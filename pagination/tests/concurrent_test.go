@@ -0,0 +1,72 @@
+package pagination_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/xans-me/gorm-pagination/pagination"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"path/filepath"
+	"testing"
+)
+
+// Setup a file backed test database, since every connection to ":memory:" sees its own database
+func setupConcurrentTestDB(t *testing.T) *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "concurrent.db")), &gorm.Config{})
+	db.AutoMigrate(&TestData{})
+
+	db.Create(&TestData{ID: 1, AccountNumber: "123", TrxDate: "2024-01-01", TrxAmount: 100, TrxType: "income", CIF: "ABC123"})
+	db.Create(&TestData{ID: 2, AccountNumber: "456", TrxDate: "2024-02-01", TrxAmount: 200, TrxType: "expense", CIF: "DEF456"})
+	db.Create(&TestData{ID: 3, AccountNumber: "789", TrxDate: "2024-03-01", TrxAmount: 300, TrxType: "income", CIF: "GHI789"})
+
+	return db
+}
+
+func TestPaginator_ConcurrentQueries(t *testing.T) {
+	db := setupConcurrentTestDB(t)
+
+	paginate := func(options ...pagination.PaginatorOption) ([]TestData, *pagination.Result) {
+		options = append([]pagination.PaginatorOption{
+			pagination.WithPageSize(2),
+			pagination.WithSort("trx_amount desc"),
+			pagination.WithSummaryFields(
+				"trx_amount:sum",
+				"trx_amount:min",
+				"trx_amount:max",
+				"trx_type:distribution",
+				"trx_type:value_count:income|expense",
+			),
+		}, options...)
+		paginator := pagination.NewPaginator(db.Model(&TestData{}), options...)
+
+		var results []TestData
+		res, err := paginator.Paginate(&results)
+		assert.Nil(t, err)
+		return results, res
+	}
+
+	sequentialData, sequential := paginate()
+	concurrentData, concurrent := paginate(pagination.WithConcurrentQueries(3))
+
+	assert.Equal(t, sequentialData, concurrentData)
+	assert.Equal(t, sequential.TotalData, concurrent.TotalData)
+	assert.Equal(t, sequential.TotalPages, concurrent.TotalPages)
+	assert.Equal(t, sequential.HasNext, concurrent.HasNext)
+	assert.Equal(t, sequential.Summary, concurrent.Summary)
+	assert.Equal(t, int64(2), concurrent.Summary["trx_type_income_count"])
+}
+
+func TestPaginator_ConcurrentQueriesError(t *testing.T) {
+	db := setupConcurrentTestDB(t)
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithSort("missing_column desc"),
+		pagination.WithSummaryFields("trx_amount:sum", "trx_amount:max"),
+		pagination.WithConcurrentQueries(2),
+	)
+
+	var results []TestData
+	_, err := paginator.Paginate(&results)
+
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,83 @@
+package pagination_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/xans-me/gorm-pagination/pagination"
+	"testing"
+)
+
+func TestPaginator_ConditionalSummaries(t *testing.T) {
+	db := setupTestDB()
+	queries := countQueries(db)
+
+	income := pagination.ComparisonFilter{Field: "trx_type", Operator: "=", Value: "income"}
+	expense := pagination.ComparisonFilter{Field: "trx_type", Operator: "=", Value: "expense"}
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithSummaries(
+			pagination.Sum("trx_amount").Where(income).As("income"),
+			pagination.Count("id").Where(expense).As("expenses"),
+			pagination.Variance("trx_amount").Where(income).As("income_variance"),
+			pagination.Net("trx_amount", income, expense),
+			pagination.CountWhere("trx_type", "income").Where(pagination.ComparisonFilter{Field: "trx_amount", Operator: ">", Value: 150}).As("large_income"),
+		),
+	)
+
+	summary, err := paginator.Summary(&[]TestData{})
+	assert.Nil(t, err)
+
+	// Conditional scalars still share one query
+	assert.Equal(t, 1, *queries)
+	assert.Equal(t, float64(400), summary["income"])
+	assert.Equal(t, int64(1), summary["expenses"])
+	assert.Equal(t, float64(20000), summary["income_variance"])
+	assert.Equal(t, float64(200), summary["trx_amount_net"])
+	assert.Equal(t, int64(1), summary["large_income"])
+}
+
+func TestPaginator_ConditionalSummariesFromString(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithSummaryFields(
+			"trx_amount:sum:where=trx_type=income|as=income",
+			"trx_amount:sum:where=trx_amount>=200|where=trx_type!=income|as=large_expense",
+			"trx_amount:net:trx_type=income|trx_type=expense",
+			"trx_type:distribution:where=trx_amount<300",
+			"trx_amount:sum:by=trx_type|where=trx_amount>100",
+		),
+	)
+
+	summary, err := paginator.Summary(&[]TestData{})
+	assert.Nil(t, err)
+
+	assert.Equal(t, float64(400), summary["income"])
+	assert.Equal(t, float64(200), summary["large_expense"])
+	assert.Equal(t, float64(200), summary["trx_amount_net"])
+	assert.Equal(t, []map[string]interface{}{
+		{"trx_type": "expense", "count": int64(1), "percent": float64(50)},
+		{"trx_type": "income", "count": int64(1), "percent": float64(50)},
+	}, summary["trx_type_distribution"])
+	assert.Equal(t, map[string]interface{}{
+		"total": float64(500),
+		"groups": []map[string]interface{}{
+			{"trx_type": "expense", "value": float64(200)},
+			{"trx_type": "income", "value": float64(300)},
+		},
+	}, summary["trx_amount_sum_by_trx_type"])
+}
+
+func TestPaginator_ConditionalSummariesInvalid(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithSummaryFields("trx_amount:sum:where=trx_type", "trx_amount:net:trx_type=income"),
+		pagination.WithSummaryErrorPolicy(pagination.SummaryErrorsReport),
+	)
+
+	_, err := paginator.Summary(&[]TestData{})
+	assert.ErrorIs(t, err, pagination.ErrInvalidSummary)
+}
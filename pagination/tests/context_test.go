@@ -0,0 +1,56 @@
+package pagination_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/xans-me/gorm-pagination/pagination"
+	"testing"
+	"time"
+)
+
+func TestPaginator_PaginateContext(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithPageSize(2),
+		pagination.WithQueryTimeout(time.Minute),
+	)
+
+	var results []TestData
+	res, err := paginator.PaginateContext(context.Background(), &results)
+
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, int64(3), res.TotalData)
+}
+
+func TestPaginator_PaginateContextCanceled(t *testing.T) {
+	db := setupTestDB()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	paginator := pagination.NewPaginator(db.Model(&TestData{}))
+
+	var results []TestData
+	_, err := paginator.PaginateContext(ctx, &results)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, pagination.ErrQueryTimeout)
+}
+
+func TestPaginator_QueryTimeout(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithQueryTimeout(time.Nanosecond),
+	)
+
+	var results []TestData
+	_, err := paginator.PaginateContext(context.Background(), &results)
+
+	assert.ErrorIs(t, err, pagination.ErrQueryTimeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,190 @@
+package pagination_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/xans-me/gorm-pagination/pagination"
+	"testing"
+)
+
+func TestPaginator_CountExact(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithPageSize(2),
+	)
+
+	var results []TestData
+	res, err := paginator.Paginate(&results)
+
+	assert.Nil(t, err)
+	assert.Equal(t, pagination.CountExact, res.CountStrategy)
+	assert.Equal(t, int64(3), res.TotalData)
+	assert.True(t, res.HasNext)
+}
+
+func TestPaginator_CountCapped(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithPageSize(1),
+		pagination.WithCappedCount(2),
+	)
+
+	var results []TestData
+	res, err := paginator.Paginate(&results)
+
+	assert.Nil(t, err)
+	assert.Equal(t, pagination.CountCapped, res.CountStrategy)
+	assert.Equal(t, int64(2), res.TotalData)
+	assert.Equal(t, "2+", res.TotalDataLabel)
+	assert.Equal(t, 2, res.TotalPages)
+	assert.True(t, res.HasNext)
+
+	paginator = pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithCappedCount(5),
+	)
+	res, err = paginator.Paginate(&results)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), res.TotalData)
+	assert.Empty(t, res.TotalDataLabel)
+}
+
+func TestPaginator_CountCappedPastCap(t *testing.T) {
+	db := setupTestDB()
+	for id := 4; id <= 6; id++ {
+		db.Create(&TestData{ID: id, AccountNumber: "999", TrxAmount: 50, TrxType: "expense"})
+	}
+
+	for page, hasNext := range map[int]bool{2: true, 3: false} {
+		paginator := pagination.NewPaginator(
+			db.Model(&TestData{}),
+			pagination.WithPage(page),
+			pagination.WithPageSize(2),
+			pagination.WithCappedCount(3),
+		)
+
+		var results []TestData
+		res, err := paginator.Paginate(&results)
+
+		assert.Nil(t, err)
+		assert.Len(t, results, 2)
+		assert.Equal(t, "3+", res.TotalDataLabel)
+		assert.Equal(t, hasNext, res.HasNext)
+		assert.Equal(t, 3, res.TotalPages)
+	}
+}
+
+func TestPaginator_CountNone(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithPageSize(2),
+		pagination.WithCountStrategy(pagination.CountNone),
+	)
+
+	var results []TestData
+	res, err := paginator.Paginate(&results)
+
+	assert.Nil(t, err)
+	assert.Equal(t, pagination.CountNone, res.CountStrategy)
+	assert.Len(t, results, 2)
+	assert.True(t, res.HasNext)
+
+	paginator.Page = 2
+	results = nil
+	res, err = paginator.Paginate(&results)
+
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.False(t, res.HasNext)
+}
+
+func TestPaginator_CountEstimated(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithCountStrategy(pagination.CountEstimated),
+	)
+
+	// Without statistics the exact count is used
+	var results []TestData
+	res, err := paginator.Paginate(&results)
+
+	assert.Nil(t, err)
+	assert.Equal(t, pagination.CountExact, res.CountStrategy)
+	assert.Equal(t, int64(3), res.TotalData)
+
+	db.Exec("ANALYZE")
+
+	res, err = paginator.Paginate(&results)
+
+	assert.Nil(t, err)
+	assert.Equal(t, pagination.CountEstimated, res.CountStrategy)
+	assert.Equal(t, int64(3), res.TotalData)
+}
+
+func TestPaginator_CountCappedEmptyPage(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithPage(3),
+		pagination.WithPageSize(1),
+		pagination.WithCappedCount(1),
+	)
+
+	var results []TestData
+	res, err := paginator.Paginate(&results)
+
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, 3, res.TotalPages)
+
+	// An empty page past the rows does not count as a known page
+	paginator.Page = 6
+	results = nil
+	res, err = paginator.Paginate(&results)
+
+	assert.Nil(t, err)
+	assert.Empty(t, results)
+	assert.False(t, res.HasNext)
+	assert.Equal(t, 1, res.TotalPages)
+}
+
+func TestPaginator_CountEstimatedStale(t *testing.T) {
+	db := setupTestDB()
+	db.Exec("ANALYZE")
+	for id := 4; id <= 6; id++ {
+		db.Create(&TestData{ID: id, AccountNumber: "999", TrxAmount: 50, TrxType: "expense"})
+	}
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithPageSize(1),
+		pagination.WithCountStrategy(pagination.CountEstimated),
+	)
+
+	// The statistics still say 3 rows, but the extra row shows more pages follow
+	for page, hasNext := range map[int]bool{3: true, 5: true, 6: false} {
+		paginator.Page = page
+		var results []TestData
+		res, err := paginator.Paginate(&results)
+
+		assert.Nil(t, err)
+		assert.Len(t, results, 1)
+		assert.Equal(t, pagination.CountEstimated, res.CountStrategy)
+		assert.Equal(t, int64(3), res.TotalData)
+		assert.Equal(t, hasNext, res.HasNext)
+		if hasNext {
+			assert.Equal(t, page+1, res.TotalPages)
+		} else {
+			assert.Equal(t, page, res.TotalPages)
+		}
+	}
+}
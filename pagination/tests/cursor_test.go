@@ -0,0 +1,176 @@
+package pagination_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/xans-me/gorm-pagination/pagination"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"testing"
+)
+
+type CursorTestData struct {
+	ID        int
+	TrxDate   string
+	TrxAmount float64
+	TrxType   string
+}
+
+// Setup test database with test data for cursor pagination tests
+func setupCursorTestDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db.AutoMigrate(&CursorTestData{})
+
+	db.Create(&CursorTestData{ID: 1, TrxDate: "2024-01-01", TrxAmount: 100, TrxType: "income"})
+	db.Create(&CursorTestData{ID: 2, TrxDate: "2024-01-02", TrxAmount: 200, TrxType: "expense"})
+	db.Create(&CursorTestData{ID: 3, TrxDate: "2024-01-02", TrxAmount: 300, TrxType: "income"})
+	db.Create(&CursorTestData{ID: 4, TrxDate: "2024-01-03", TrxAmount: 400, TrxType: "income"})
+	db.Create(&CursorTestData{ID: 5, TrxDate: "2024-01-03", TrxAmount: 500, TrxType: "expense"})
+
+	return db
+}
+
+func cursorPage(t *testing.T, db *gorm.DB, cursor string, options ...pagination.PaginatorOption) ([]int, *pagination.Result) {
+	options = append([]pagination.PaginatorOption{
+		pagination.WithPageSize(2),
+		pagination.WithSort("trx_date desc", "id desc"),
+		pagination.WithCursor(cursor),
+	}, options...)
+	paginator := pagination.NewPaginator(db.Model(&CursorTestData{}), options...)
+
+	var results []CursorTestData
+	res, err := paginator.Paginate(&results)
+	assert.Nil(t, err)
+
+	var ids []int
+	for _, r := range results {
+		ids = append(ids, r.ID)
+	}
+	return ids, res
+}
+
+func TestPaginator_CursorForwardAndBackward(t *testing.T) {
+	db := setupCursorTestDB()
+
+	ids, res := cursorPage(t, db, "")
+	assert.Equal(t, []int{5, 4}, ids)
+	assert.Empty(t, res.PrevCursor)
+	assert.NotEmpty(t, res.NextCursor)
+	assert.Equal(t, int64(5), res.TotalData)
+
+	ids, res = cursorPage(t, db, res.NextCursor)
+	assert.Equal(t, []int{3, 2}, ids)
+	assert.NotEmpty(t, res.PrevCursor)
+	next := res.NextCursor
+
+	ids, res = cursorPage(t, db, next)
+	assert.Equal(t, []int{1}, ids)
+	assert.Empty(t, res.NextCursor)
+
+	ids, res = cursorPage(t, db, res.PrevCursor)
+	assert.Equal(t, []int{3, 2}, ids)
+	assert.NotEmpty(t, res.NextCursor)
+
+	ids, res = cursorPage(t, db, res.PrevCursor)
+	assert.Equal(t, []int{5, 4}, ids)
+	assert.Empty(t, res.PrevCursor)
+	assert.NotEmpty(t, res.NextCursor)
+}
+
+func TestPaginator_CursorWithFilters(t *testing.T) {
+	db := setupCursorTestDB()
+	income := pagination.WithFilters(pagination.ComparisonFilter{Field: "trx_type", Operator: "=", Value: "income"})
+
+	ids, res := cursorPage(t, db, "", income)
+	assert.Equal(t, []int{4, 3}, ids)
+	assert.Equal(t, int64(3), res.TotalData)
+
+	ids, res = cursorPage(t, db, res.NextCursor, income)
+	assert.Equal(t, []int{1}, ids)
+	assert.Empty(t, res.NextCursor)
+}
+
+func TestPaginator_InvalidCursor(t *testing.T) {
+	db := setupCursorTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&CursorTestData{}),
+		pagination.WithSort("trx_date desc"),
+		pagination.WithCursor("not-a-cursor"),
+	)
+
+	var results []CursorTestData
+	_, err := paginator.Paginate(&results)
+
+	assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
+}
+
+type NullableCursorTestData struct {
+	ID   int
+	Note *string
+}
+
+func TestPaginator_CursorNullSortKey(t *testing.T) {
+	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db.AutoMigrate(&NullableCursorTestData{})
+
+	b, a := "b", "a"
+	db.Create(&NullableCursorTestData{ID: 1})
+	db.Create(&NullableCursorTestData{ID: 2, Note: &b})
+	db.Create(&NullableCursorTestData{ID: 3})
+	db.Create(&NullableCursorTestData{ID: 4, Note: &a})
+	db.Create(&NullableCursorTestData{ID: 5})
+	db.Create(&NullableCursorTestData{ID: 6, Note: &b})
+
+	page := func(sort, cursor string) ([]int, *pagination.Result) {
+		paginator := pagination.NewPaginator(
+			db.Model(&NullableCursorTestData{}),
+			pagination.WithPageSize(2),
+			pagination.WithSort(sort),
+			pagination.WithCursor(cursor),
+		)
+
+		var results []NullableCursorTestData
+		res, err := paginator.Paginate(&results)
+		assert.Nil(t, err)
+
+		var ids []int
+		for _, r := range results {
+			ids = append(ids, r.ID)
+		}
+		return ids, res
+	}
+
+	tests := []struct {
+		sort  string
+		pages [][]int
+	}{
+		{"note asc", [][]int{{4, 2}, {6, 1}, {3, 5}}},
+		{"note desc", [][]int{{5, 3}, {1, 6}, {2, 4}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			ids, res := page(tt.sort, "")
+			assert.Equal(t, tt.pages[0], ids)
+			for _, want := range tt.pages[1:] {
+				ids, res = page(tt.sort, res.NextCursor)
+				assert.Equal(t, want, ids)
+			}
+			assert.Empty(t, res.NextCursor)
+
+			for i := len(tt.pages) - 2; i >= 0; i-- {
+				ids, res = page(tt.sort, res.PrevCursor)
+				assert.Equal(t, tt.pages[i], ids)
+			}
+			assert.Empty(t, res.PrevCursor)
+		})
+	}
+
+	// Every sort key NULL
+	db.Model(&NullableCursorTestData{}).Where("1 = 1").Update("note", nil)
+	ids, res := page("note asc", "")
+	assert.Equal(t, []int{1, 2}, ids)
+	ids, res = page("note asc", res.NextCursor)
+	assert.Equal(t, []int{3, 4}, ids)
+	ids, _ = page("note asc", res.NextCursor)
+	assert.Equal(t, []int{5, 6}, ids)
+}
@@ -0,0 +1,95 @@
+package pagination_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/xans-me/gorm-pagination/pagination"
+	"testing"
+)
+
+func TestPaginator_DecimalSummaries(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithDecimalSummaries(pagination.DecimalString),
+		pagination.WithSummaryFields("trx_amount:sum", "trx_amount:max", "trx_amount:count"),
+	)
+
+	summary, err := paginator.Summary(&[]TestData{})
+	assert.Nil(t, err)
+
+	assert.Equal(t, pagination.Decimal{Value: "600", Valid: true, Encoding: pagination.DecimalString}, summary["trx_amount_sum"])
+	assert.Equal(t, int64(3), summary["trx_amount_count"])
+
+	data, err := json.Marshal(summary)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"trx_amount_sum":"600","trx_amount_max":"300","trx_amount_count":3}`, string(data))
+}
+
+func TestPaginator_DecimalSummariesNull(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithFilters(pagination.ComparisonFilter{Field: "trx_amount", Operator: ">", Value: 1000}),
+		pagination.WithDecimalSummaries(pagination.DecimalNumber),
+		pagination.WithSummaryFields("trx_amount:sum", "trx_amount:stddev"),
+	)
+
+	summary, err := paginator.Summary(&[]TestData{})
+	assert.Nil(t, err)
+
+	// A SUM over no rows is NULL rather than zero
+	data, err := json.Marshal(summary)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"trx_amount_sum":null,"trx_amount_stddev":null}`, string(data))
+}
+
+func TestDecimal_JSONRoundTrip(t *testing.T) {
+	for _, encoded := range []string{`"1234.50"`, `1234.50`, `null`} {
+		var decimal pagination.Decimal
+		assert.Nil(t, json.Unmarshal([]byte(encoded), &decimal))
+
+		data, err := json.Marshal(decimal)
+		assert.Nil(t, err)
+		assert.Equal(t, encoded, string(data))
+	}
+
+	var decimal pagination.Decimal
+	assert.NotNil(t, json.Unmarshal([]byte(`"abc"`), &decimal))
+}
+
+func TestPaginator_DecimalAggregations(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithDecimalSummaries(pagination.DecimalString),
+		pagination.WithSummaries(
+			pagination.Median("trx_amount"),
+			pagination.Distribution("trx_type").RankedBy("trx_amount").Top(1),
+			pagination.Range("trx_amount", 0, 250, 1000).WithSum("trx_amount"),
+		),
+	)
+
+	summary, err := paginator.Summary(&[]TestData{})
+	assert.Nil(t, err)
+
+	// Percentiles interpolated in Go, ranked sums and histogram sums are Decimals too, with
+	// NULL for the sum of an empty bucket
+	data, err := json.Marshal(summary)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"trx_amount_median": "200",
+		"trx_type_distribution": [
+			{"trx_type": "income", "count": 2, "percent": 66.67, "trx_amount_sum": "400"},
+			{"trx_type": null, "count": 1, "percent": 33.33, "trx_amount_sum": "200", "other": true}
+		],
+		"trx_amount_range": [
+			{"from": 0, "to": 250, "count": 2, "trx_amount_sum": "300"},
+			{"from": 250, "to": 1000, "count": 1, "trx_amount_sum": "300"},
+			{"from": 1000, "to": null, "count": 0, "trx_amount_sum": null}
+		]
+	}`, string(data))
+}
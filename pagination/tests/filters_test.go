@@ -62,3 +62,154 @@ func TestSearchFilter(t *testing.T) {
 	assert.Len(t, results, 1)
 	assert.Equal(t, "john@example.com", results[0].Email)
 }
+
+func TestFilterTree(t *testing.T) {
+	db := setupFilterTestDB()
+
+	tree := pagination.Or(
+		pagination.And(
+			pagination.ComparisonFilter{Field: "name", Operator: "=", Value: "John"},
+			pagination.ComparisonFilter{Field: "age", Operator: ">", Value: 25},
+		),
+		pagination.And(
+			pagination.ComparisonFilter{Field: "name", Operator: "=", Value: "Jane"},
+			pagination.Not(pagination.ComparisonFilter{Field: "age", Operator: ">=", Value: 30}),
+		),
+	)
+
+	var results []FilterTestData
+	err := tree.Apply(db.Model(&FilterTestData{})).Order("id").Find(&results).Error
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+
+	// The tree stays one condition when combined with other filters
+	sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		query := pagination.ComparisonFilter{Field: "id", Operator: "!=", Value: 1}.Apply(tx.Model(&FilterTestData{}))
+		return tree.Apply(query).Find(&[]FilterTestData{})
+	})
+	assert.Equal(t, "SELECT * FROM `filter_test_data` WHERE `id` != 1 AND "+
+		"(((`name` = \"John\") AND (`age` > 25)) OR ((`name` = \"Jane\") AND (NOT (`age` >= 30))))", sql)
+
+	err = tree.Apply(pagination.ComparisonFilter{Field: "id", Operator: "!=", Value: 1}.Apply(db.Model(&FilterTestData{}))).
+		Find(&results).Error
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "Jane", results[0].Name)
+}
+
+func TestFilterTreeSingleOr(t *testing.T) {
+	db := setupFilterTestDB()
+
+	query := pagination.ComparisonFilter{Field: "age", Operator: ">", Value: 26}.Apply(db.Model(&FilterTestData{}))
+	query = pagination.Or(pagination.ComparisonFilter{Field: "name", Operator: "=", Value: "Doe"}).Apply(query)
+
+	var results []FilterTestData
+	assert.Nil(t, query.Find(&results).Error)
+	assert.Len(t, results, 1)
+}
+
+// nameFilter is a user-defined filter without an expression of its own
+type nameFilter struct{ name string }
+
+func (f nameFilter) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("name = ?", f.name)
+}
+
+// noopFilter adds no condition, so it cannot be expressed
+type noopFilter struct{}
+
+func (noopFilter) Apply(db *gorm.DB) *gorm.DB {
+	return db
+}
+
+func TestFilterManagerOrGroup(t *testing.T) {
+	db := setupFilterTestDB()
+
+	filterManager := pagination.FilterManager{}
+	filterManager.AddAndFilter(pagination.DateRangeFilter{Field: "age", StartDate: "20", EndDate: "32"})
+	filterManager.AddOrFilter(pagination.SearchFilter{Field: "email", Value: "jane"})
+	filterManager.AddOrFilter(pagination.StatusFilter{Field: "name", Statuses: []string{"Doe", "John"}})
+	filterManager.AddOrFilter(nameFilter{name: "Nobody"})
+
+	var results []FilterTestData
+	err := filterManager.Apply(db.Model(&FilterTestData{})).Order("id").Find(&results).Error
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "John", results[0].Name)
+	assert.Equal(t, "Jane", results[1].Name)
+
+	results = nil
+	err = pagination.Not(nameFilter{name: "John"}).Apply(db.Model(&FilterTestData{})).Find(&results).Error
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+}
+
+func TestFilterUnsupported(t *testing.T) {
+	db := setupFilterTestDB()
+
+	filterManager := pagination.FilterManager{}
+	filterManager.AddOrFilter(noopFilter{})
+
+	var results []FilterTestData
+	err := filterManager.Apply(db.Model(&FilterTestData{})).Find(&results).Error
+	assert.ErrorIs(t, err, pagination.ErrUnsupportedFilter)
+
+	_, err = pagination.FilterExpression(db, pagination.Or(pagination.ComparisonFilter{Field: "age", Operator: ">", Value: 1}, noopFilter{}))
+	assert.ErrorIs(t, err, pagination.ErrUnsupportedFilter)
+}
+
+type OperatorTestData struct {
+	ID    int
+	Code  string
+	Note  *string
+	Score float64
+}
+
+func setupOperatorTestDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db.AutoMigrate(&OperatorTestData{})
+
+	note := "50% off!"
+	db.Create(&OperatorTestData{ID: 1, Code: "ABC_1", Note: &note, Score: 9})
+	db.Create(&OperatorTestData{ID: 2, Code: "ABCX1", Score: 10})
+	db.Create(&OperatorTestData{ID: 3, Code: "xyz-abc", Score: 100})
+
+	return db
+}
+
+func TestOperatorFilters(t *testing.T) {
+	db := setupOperatorTestDB()
+
+	tests := []struct {
+		name   string
+		filter pagination.Filter
+		ids    []int
+	}{
+		{"is null", pagination.NullFilter{Field: "note"}, []int{2, 3}},
+		{"is not null", pagination.NullFilter{Field: "note", Not: true}, []int{1}},
+		{"in", pagination.InFilter{Field: "id", Values: []interface{}{1, 3}}, []int{1, 3}},
+		{"not in", pagination.InFilter{Field: "id", Values: []interface{}{1, 3}, Not: true}, []int{2}},
+		{"in nothing", pagination.InFilter{Field: "id"}, []int{}},
+		{"not in nothing", pagination.InFilter{Field: "id", Not: true}, []int{1, 2, 3}},
+		{"between", pagination.BetweenFilter{Field: "score", Low: 9.5, High: 100}, []int{2, 3}},
+		{"starts with escapes _", pagination.StartsWithFilter{Field: "code", Value: "ABC_"}, []int{1}},
+		{"ends with", pagination.EndsWithFilter{Field: "code", Value: "abc"}, []int{3}},
+		{"contains ignores case", pagination.ContainsFilter{Field: "code", Value: "abc"}, []int{1, 2, 3}},
+		{"search escapes %", pagination.SearchFilter{Field: "note", Value: "0%"}, []int{1}},
+		{"search escapes _", pagination.SearchFilter{Field: "code", Value: "C_"}, []int{1}},
+		{"search escapes !", pagination.SearchFilter{Field: "note", Value: "off!"}, []int{1}},
+		{"like", pagination.LikeFilter{Field: "code", Pattern: "ABC_1"}, []int{1, 2}},
+		{"like escapes", pagination.LikeFilter{Field: "code", Pattern: "ABC!_%"}, []int{1}},
+		{"not like", pagination.LikeFilter{Field: "code", Pattern: "ABC%", Not: true}, []int{3}},
+		{"inside or", pagination.Or(pagination.NullFilter{Field: "note", Not: true}, pagination.EndsWithFilter{Field: "code", Value: "abc"}), []int{1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []int
+			err := tt.filter.Apply(db.Model(&OperatorTestData{})).Order("id").Pluck("id", &ids).Error
+			assert.Nil(t, err)
+			assert.Equal(t, tt.ids, ids)
+		})
+	}
+}
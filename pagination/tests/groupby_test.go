@@ -45,3 +45,26 @@ func TestPaginator_GroupBy(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 1, res.TotalPages) // Adjust the expectation to 1
 }
+
+func TestPaginator_GroupByCountsGroups(t *testing.T) {
+	db := setupGroupByTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&GroupByTestData{}),
+		pagination.WithPageSize(1),
+		pagination.WithGroupBy("account_number"),
+		pagination.WithFilters(pagination.ComparisonFilter{
+			Field:    "trx_amount",
+			Operator: ">=",
+			Value:    100,
+		}),
+	)
+
+	var results []GroupByTestData
+	res, err := paginator.Paginate(&results)
+
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, int64(2), res.TotalData)
+	assert.Equal(t, 2, res.TotalPages)
+}
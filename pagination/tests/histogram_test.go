@@ -0,0 +1,226 @@
+package pagination_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/xans-me/gorm-pagination/pagination"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+type HistogramTestData struct {
+	ID        int
+	TrxDate   time.Time
+	TrxAmount float64
+}
+
+// Setup test database with test data for histogram tests
+func setupHistogramTestDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db.AutoMigrate(&HistogramTestData{})
+
+	db.Create(&HistogramTestData{ID: 1, TrxDate: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), TrxAmount: 100})
+	db.Create(&HistogramTestData{ID: 2, TrxDate: time.Date(2024, 1, 1, 20, 30, 0, 0, time.UTC), TrxAmount: 200})
+	db.Create(&HistogramTestData{ID: 3, TrxDate: time.Date(2024, 1, 4, 12, 0, 0, 0, time.UTC), TrxAmount: 300})
+	db.Create(&HistogramTestData{ID: 4, TrxDate: time.Date(2024, 2, 10, 12, 0, 0, 0, time.UTC), TrxAmount: 400})
+
+	return db
+}
+
+func TestPaginator_DateHistogramDaily(t *testing.T) {
+	db := setupHistogramTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&HistogramTestData{}),
+		pagination.WithFilters(pagination.ComparisonFilter{Field: "id", Operator: "<=", Value: 3}),
+		pagination.WithSummaries(pagination.DateHistogram("trx_date", "day").WithSum("trx_amount").As("daily")),
+	)
+
+	summary, err := paginator.Summary(&[]HistogramTestData{})
+	assert.Nil(t, err)
+
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	assert.Equal(t, []map[string]interface{}{
+		{"bucket": day(1), "count": int64(2), "trx_amount_sum": float64(300)},
+		{"bucket": day(2), "count": int64(0), "trx_amount_sum": float64(0)},
+		{"bucket": day(3), "count": int64(0), "trx_amount_sum": float64(0)},
+		{"bucket": day(4), "count": int64(1), "trx_amount_sum": float64(300)},
+	}, summary["daily"])
+}
+
+func TestPaginator_DateHistogramTimeZone(t *testing.T) {
+	db := setupHistogramTestDB()
+	jakarta := time.FixedZone("WIB", 7*60*60)
+
+	paginator := pagination.NewPaginator(
+		db.Model(&HistogramTestData{}),
+		pagination.WithFilters(pagination.ComparisonFilter{Field: "id", Operator: "<=", Value: 2}),
+		pagination.WithSummaries(pagination.DateHistogram("trx_date", "day").In(jakarta)),
+	)
+
+	summary, err := paginator.Summary(&[]HistogramTestData{})
+	assert.Nil(t, err)
+
+	// 20:30 UTC is already the next day in Jakarta
+	assert.Equal(t, []map[string]interface{}{
+		{"bucket": time.Date(2024, 1, 1, 0, 0, 0, 0, jakarta), "count": int64(1)},
+		{"bucket": time.Date(2024, 1, 2, 0, 0, 0, 0, jakarta), "count": int64(1)},
+	}, summary["trx_date_date_histogram"])
+}
+
+func TestPaginator_DateHistogramWeeklyAndMonthly(t *testing.T) {
+	db := setupHistogramTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&HistogramTestData{}),
+		pagination.WithSummaryFields("trx_date:date_histogram:week"),
+		pagination.WithSummaries(pagination.DateHistogram("trx_date", "month").As("monthly")),
+	)
+
+	summary, err := paginator.Summary(&[]HistogramTestData{})
+	assert.Nil(t, err)
+
+	// Weeks start on Monday: 2024-01-01 is a Monday, 2024-02-10 falls in the week of 2024-02-05
+	weekly := summary["trx_date_date_histogram"].([]map[string]interface{})
+	assert.Len(t, weekly, 6)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), weekly[0]["bucket"])
+	assert.Equal(t, int64(3), weekly[0]["count"])
+	assert.Equal(t, time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC), weekly[5]["bucket"])
+	assert.Equal(t, int64(1), weekly[5]["count"])
+
+	assert.Equal(t, []map[string]interface{}{
+		{"bucket": time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "count": int64(3)},
+		{"bucket": time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), "count": int64(1)},
+	}, summary["monthly"])
+}
+
+func TestParseSummaryField_DateHistogram(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	assert.Nil(t, err)
+
+	assert.Equal(t,
+		[]pagination.SummarySpec{pagination.DateHistogram("trx_date", "month").In(jakarta).WithSum("trx_amount", "fee")},
+		pagination.ParseSummaryField("trx_date:date_histogram:month|tz=Asia/Jakarta|sum=trx_amount,fee"),
+	)
+	assert.Equal(t,
+		[]pagination.SummarySpec{pagination.DateHistogram("trx_date", "week")},
+		pagination.ParseSummaryField("trx_date:date_histogram:interval=week"),
+	)
+}
+
+func TestPaginator_DateHistogramInvalidInterval(t *testing.T) {
+	db := setupHistogramTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&HistogramTestData{}),
+		pagination.WithSummaryFields("trx_date:date_histogram:fortnight"),
+	)
+
+	_, err := paginator.Summary(&[]HistogramTestData{})
+	assert.ErrorIs(t, err, pagination.ErrInvalidSummary)
+}
+
+func TestPaginator_DateHistogramEmpty(t *testing.T) {
+	db := setupHistogramTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&HistogramTestData{}),
+		pagination.WithFilters(pagination.ComparisonFilter{Field: "id", Operator: ">", Value: 10}),
+		pagination.WithSummaryFields("trx_date:date_histogram:day"),
+	)
+
+	summary, err := paginator.Summary(&[]HistogramTestData{})
+	assert.Nil(t, err)
+
+	data, err := json.Marshal(summary)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"trx_date_date_histogram":[]}`, string(data))
+}
+
+func TestPaginator_DateHistogramDaylightSaving(t *testing.T) {
+	db := setupHistogramTestDB()
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.Nil(t, err)
+
+	// A single UTC offset cannot bucket both sides of a daylight saving change on SQLite
+	paginator := pagination.NewPaginator(
+		db.Model(&HistogramTestData{}),
+		pagination.WithSummaries(pagination.DateHistogram("trx_date", "day").In(newYork)),
+	)
+
+	_, err = paginator.Summary(&[]HistogramTestData{})
+	assert.ErrorIs(t, err, pagination.ErrInvalidSummary)
+}
+
+func TestPaginator_RangeHistogram(t *testing.T) {
+	db := setupHistogramTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&HistogramTestData{}),
+		pagination.WithSummaries(pagination.Range("trx_amount", 150, 300, 1000).WithSum("trx_amount").As("amounts")),
+	)
+
+	summary, err := paginator.Summary(&[]HistogramTestData{})
+	assert.Nil(t, err)
+
+	// 100 is below the first edge and not counted
+	assert.Equal(t, []map[string]interface{}{
+		{"from": float64(150), "to": float64(300), "count": int64(1), "trx_amount_sum": float64(200)},
+		{"from": float64(300), "to": float64(1000), "count": int64(2), "trx_amount_sum": float64(700)},
+		{"from": float64(1000), "to": nil, "count": int64(0), "trx_amount_sum": float64(0)},
+	}, summary["amounts"])
+}
+
+func TestPaginator_EqualWidthHistogram(t *testing.T) {
+	db := setupHistogramTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&HistogramTestData{}),
+		pagination.WithSummaryFields("trx_amount:histogram:3"),
+	)
+
+	summary, err := paginator.Summary(&[]HistogramTestData{})
+	assert.Nil(t, err)
+
+	// The last bucket includes the maximum
+	assert.Equal(t, []map[string]interface{}{
+		{"from": float64(100), "to": float64(200), "count": int64(1)},
+		{"from": float64(200), "to": float64(300), "count": int64(1)},
+		{"from": float64(300), "to": float64(400), "count": int64(2)},
+	}, summary["trx_amount_histogram"])
+}
+
+func TestPaginator_HistogramEmpty(t *testing.T) {
+	db := setupEdgeCaseTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&EdgeCaseTestData{}),
+		pagination.WithSummaryFields("trx_amount:histogram:4"),
+	)
+
+	summary, err := paginator.Summary(&[]EdgeCaseTestData{})
+	assert.Nil(t, err)
+
+	// Encoded as an empty list rather than null
+	data, err := json.Marshal(summary)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"trx_amount_histogram":[]}`, string(data))
+}
+
+func TestParseSummaryField_Range(t *testing.T) {
+	specs := pagination.ParseSummaryField("trx_amount:range:0|100|1000|sum=trx_amount")
+	assert.Equal(t, []pagination.SummarySpec{
+		pagination.Range("trx_amount", 0, 100, 1000).WithSum("trx_amount"),
+	}, specs)
+
+	paginator := pagination.NewPaginator(
+		setupHistogramTestDB().Model(&HistogramTestData{}),
+		pagination.WithSummaryFields("trx_amount:range:100|abc", "trx_amount:histogram:0"),
+		pagination.WithSummaryErrorPolicy(pagination.SummaryErrorsReport),
+	)
+
+	_, err := paginator.Summary(&[]HistogramTestData{})
+	assert.ErrorIs(t, err, pagination.ErrInvalidSummary)
+}
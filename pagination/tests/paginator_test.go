@@ -93,3 +93,28 @@ func TestPaginator_Summary(t *testing.T) {
 	assert.Equal(t, float64(300), res.Summary["trx_amount_max"])
 	assert.Equal(t, float64(600), res.Summary["trx_amount_sum"])
 }
+
+func TestPaginator_FiltersApplyToCountAndSummary(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithPage(2),
+		pagination.WithPageSize(1),
+		pagination.WithFilters(pagination.ComparisonFilter{
+			Field:    "trx_type",
+			Operator: "=",
+			Value:    "income",
+		}),
+		pagination.WithSummaryFields("trx_amount:sum"),
+	)
+
+	var results []TestData
+	res, err := paginator.Paginate(&results)
+
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, int64(2), res.TotalData)
+	assert.Equal(t, 2, res.TotalPages)
+	assert.Equal(t, float64(400), res.Summary["trx_amount_sum"])
+}
@@ -0,0 +1,105 @@
+package pagination_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/xans-me/gorm-pagination/pagination"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	filter, err := pagination.ParseFilter("trx_type==income;trx_amount=ge=100,(account_number=in=(123,456))")
+	assert.Nil(t, err)
+	assert.Equal(t, pagination.Or(
+		pagination.And(
+			pagination.ComparisonFilter{Field: "trx_type", Operator: "=", Value: "income"},
+			pagination.ComparisonFilter{Field: "trx_amount", Operator: ">=", Value: "100"},
+		),
+		pagination.InFilter{Field: "account_number", Values: []interface{}{"123", "456"}},
+	), filter)
+
+	filter, err = pagination.ParseFilter(`!(cif=isnull=true,cif=like="A %") ; trx_date=between=(2024-01-01,'2024-01-31')`)
+	assert.Nil(t, err)
+	assert.Equal(t, pagination.And(
+		pagination.Not(pagination.Or(
+			pagination.NullFilter{Field: "cif"},
+			pagination.LikeFilter{Field: "cif", Pattern: "A %"},
+		)),
+		pagination.DateRangeFilter{Field: "trx_date", StartDate: "2024-01-01", EndDate: "2024-01-31"},
+	), filter)
+
+	filter, err = pagination.ParseFilter("")
+	assert.Nil(t, err)
+	assert.Equal(t, pagination.And(), filter)
+}
+
+func TestParseFilterQuery(t *testing.T) {
+	db := setupFilterTestDB()
+
+	filter, err := pagination.ParseFilter("name==Doe,(age=lt=30;email=contains=JANE)")
+	assert.Nil(t, err)
+
+	var names []string
+	err = filter.Apply(db.Model(&FilterTestData{})).Order("id").Pluck("name", &names).Error
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Jane", "Doe"}, names)
+}
+
+func TestParseFilterSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		query    string
+		position int
+		message  string
+	}{
+		{"trx_type=income", 8, `expected an operator after "trx_type"`},
+		{"trx_type==income;", 17, "expected a field"},
+		{"(trx_type==income", 0, "unclosed ("},
+		{"trx_type=foo=income", 8, `unknown operator "=foo="`},
+		{"trx_amount=between=(1)", 19, "=between= takes two values"},
+		{"cif=='abc", 5, "unterminated quoted value"},
+		{"cif==a b", 7, `unexpected 'b'`},
+		{"cif=in=(a;b)", 9, "expected , or ) in the list of values"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := pagination.ParseFilter(tt.query)
+
+			var syntaxErr *pagination.FilterSyntaxError
+			if assert.ErrorAs(t, err, &syntaxErr) {
+				assert.Equal(t, tt.position, syntaxErr.Position)
+				assert.Equal(t, tt.message, syntaxErr.Message)
+			}
+		})
+	}
+}
+
+func TestFormatFilter(t *testing.T) {
+	filterManager := &pagination.FilterManager{}
+	filterManager.AddAndFilter(pagination.StatusFilter{Field: "trx_type", Statuses: []string{"income", "expense"}})
+	filterManager.AddOrFilter(pagination.ComparisonFilter{Field: "trx_amount", Operator: ">", Value: 100})
+	filterManager.AddOrFilter(pagination.Not(pagination.SearchFilter{Field: "cif", Value: "a;b"}))
+
+	query, err := pagination.FormatFilter(filterManager)
+	assert.Nil(t, err)
+	assert.Equal(t, `trx_type=in=(income,expense);(trx_amount=gt=100,!(cif=search="a;b"))`, query)
+
+	// Formatting the parsed query gives it back
+	for _, query := range []string{
+		"trx_type==income;trx_amount=ge=100,account_number=in=(123,456)",
+		`(a==1,b!=2);c=out=();!(d=isnull=false;e=starts="x y",f=ends='\'')`,
+		"g=between=(1,2);h=notlike=%x_",
+	} {
+		filter, err := pagination.ParseFilter(query)
+		assert.Nil(t, err)
+
+		formatted, err := pagination.FormatFilter(filter)
+		assert.Nil(t, err)
+
+		reparsed, err := pagination.ParseFilter(formatted)
+		assert.Nil(t, err)
+		assert.Equal(t, filter, reparsed)
+	}
+
+	_, err = pagination.FormatFilter(pagination.Or(nameFilter{name: "John"}))
+	assert.ErrorIs(t, err, pagination.ErrUnsupportedFilter)
+}
@@ -0,0 +1,95 @@
+package pagination_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/xans-me/gorm-pagination/pagination"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"testing"
+)
+
+type ScopeTestData struct {
+	ID        int
+	TrxType   string
+	TrxAmount float64
+}
+
+// Setup test database with test data for summary scope tests
+func setupScopeTestDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db.AutoMigrate(&ScopeTestData{})
+
+	for i := 1; i <= 5; i++ {
+		trxType := "income"
+		if i%2 == 0 {
+			trxType = "expense"
+		}
+		db.Create(&ScopeTestData{ID: i, TrxType: trxType, TrxAmount: float64(i * 100)})
+	}
+
+	return db
+}
+
+func TestPaginator_PageSummary(t *testing.T) {
+	db := setupScopeTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&ScopeTestData{}),
+		pagination.WithPage(2),
+		pagination.WithPageSize(2),
+		pagination.WithSort("id desc"),
+		pagination.WithSummaryFields("trx_amount:sum:scope=both", "trx_amount:max:scope=page", "id:count"),
+		pagination.WithSummaries(pagination.Distribution("trx_type").WithScope(pagination.SummaryScopePage)),
+	)
+
+	var results []ScopeTestData
+	res, err := paginator.Paginate(&results)
+	assert.Nil(t, err)
+
+	// The second page holds the rows 3 and 2
+	assert.Equal(t, map[string]interface{}{"trx_amount_sum": float64(1500), "id_count": int64(5)}, res.Summary)
+	assert.Equal(t, float64(500), res.PageSummary["trx_amount_sum"])
+	assert.Equal(t, float64(300), res.PageSummary["trx_amount_max"])
+	assert.Equal(t, []map[string]interface{}{
+		{"trx_type": "expense", "count": int64(1), "percent": float64(50)},
+		{"trx_type": "income", "count": int64(1), "percent": float64(50)},
+	}, res.PageSummary["trx_type_distribution"])
+}
+
+func TestPaginator_PageSummaryWithCursor(t *testing.T) {
+	db := setupScopeTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&ScopeTestData{}),
+		pagination.WithPageSize(2),
+		pagination.WithSort("id asc"),
+		pagination.WithCursor(""),
+		pagination.WithSummaryScope(pagination.SummaryScopeBoth),
+		pagination.WithSummaryFields("trx_amount:sum"),
+	)
+
+	var first []ScopeTestData
+	res, err := paginator.Paginate(&first)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(300), res.PageSummary["trx_amount_sum"])
+
+	paginator.Cursor = res.NextCursor
+	var second []ScopeTestData
+	res, err = paginator.Paginate(&second)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(700), res.PageSummary["trx_amount_sum"])
+	assert.Equal(t, float64(1500), res.Summary["trx_amount_sum"])
+}
+
+func TestPaginator_SummaryUnknownScope(t *testing.T) {
+	db := setupScopeTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&ScopeTestData{}),
+		pagination.WithSummaryFields("trx_amount:sum:scope=everything"),
+	)
+
+	var results []ScopeTestData
+	_, err := paginator.Paginate(&results)
+	assert.ErrorIs(t, err, pagination.ErrInvalidSummary)
+}
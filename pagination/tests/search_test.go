@@ -0,0 +1,108 @@
+package pagination_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/xans-me/gorm-pagination/pagination"
+	"testing"
+)
+
+func TestPaginator_DecodeSearch(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(db.Model(&TestData{}), pagination.WithModelFields())
+	err := paginator.DecodeSearch([]byte(`{
+		"trx_amount": {"$gte": "100", "$lt": 300},
+		"$or": [{"trx_type": "expense"}, {"cif": {"$like": "ABC%"}}],
+		"account_number": {"$nin": [789]},
+		"$sort": "trx_amount desc",
+		"$pageSize": 1
+	}`), &TestData{})
+	assert.Nil(t, err)
+
+	assert.Equal(t, []pagination.Filter{pagination.And(
+		pagination.Or(
+			pagination.ComparisonFilter{Field: "trx_type", Operator: "=", Value: "expense"},
+			pagination.LikeFilter{Field: "cif", Pattern: "ABC%"},
+		),
+		pagination.InFilter{Field: "account_number", Values: []interface{}{"789"}, Not: true},
+		pagination.And(
+			pagination.ComparisonFilter{Field: "trx_amount", Operator: ">=", Value: float64(100)},
+			pagination.ComparisonFilter{Field: "trx_amount", Operator: "<", Value: float64(300)},
+		),
+	)}, paginator.Filters)
+
+	var results []TestData
+	res, err := paginator.Paginate(&results)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), res.TotalData)
+	assert.Equal(t, 2, res.TotalPages)
+	assert.Equal(t, 200.0, results[0].TrxAmount)
+}
+
+func TestPaginator_DecodeSearchOperators(t *testing.T) {
+	db := setupTestDB()
+
+	tests := []struct {
+		search string
+		ids    []int
+	}{
+		{`{"cif": {"$starts": "GHI"}}`, []int{3}},
+		{`{"cif": {"$contains": "def"}}`, []int{2}},
+		{`{"trx_amount": {"$between": [150, "300"]}}`, []int{2, 3}},
+		{`{"$not": {"trx_type": "income"}}`, []int{2}},
+		{`{"cif": {"$exists": true}, "id": {"$in": [1, "2"]}}`, []int{1, 2}},
+		{`{"cif": null}`, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.search, func(t *testing.T) {
+			paginator := pagination.NewPaginator(db.Model(&TestData{}), pagination.WithSort("id"))
+			assert.Nil(t, paginator.DecodeSearch([]byte(tt.search), &TestData{}))
+
+			var results []TestData
+			_, err := paginator.Paginate(&results)
+			assert.Nil(t, err)
+
+			ids := []int{}
+			for _, result := range results {
+				ids = append(ids, result.ID)
+			}
+			assert.Equal(t, tt.ids, ids)
+		})
+	}
+}
+
+func TestPaginator_DecodeSearchErrors(t *testing.T) {
+	db := setupTestDB()
+
+	tests := []struct {
+		search string
+		kind   string
+		value  string
+	}{
+		{`{"password": "secret"}`, "field", "password"},
+		{`{"$sort": ["password desc"]}`, "field", "password"},
+		{`{"trx_amount": {"$gte": "a lot"}}`, "value", `"a lot"`},
+		{`{"trx_amount": {"$regex": "1.*"}}`, "operator", "$regex"},
+		{`{"$where": "1 = 1"}`, "operator", "$where"},
+		{`{"$or": []}`, "value", "[]"},
+		{`{"id": {"$between": [1]}}`, "value", "[1]"},
+		{`{"$page": 0}`, "value", "0"},
+		{`[1]`, "value", "[1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.search, func(t *testing.T) {
+			paginator := pagination.NewPaginator(db.Model(&TestData{}), pagination.WithAllowedFields("id", "trx_amount", "cif"))
+			err := paginator.DecodeSearch([]byte(tt.search), &TestData{})
+
+			var validationErr *pagination.ValidationError
+			if assert.ErrorAs(t, err, &validationErr) {
+				assert.Equal(t, tt.kind, validationErr.Kind)
+				assert.Equal(t, tt.value, validationErr.Value)
+			}
+			assert.Empty(t, paginator.Filters)
+			assert.Empty(t, paginator.Sort)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package pagination_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/xans-me/gorm-pagination/pagination"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"math"
+	"testing"
+)
+
+type StatisticsTestData struct {
+	ID        int
+	TrxAmount float64
+	TrxType   string
+	Quantity  int
+}
+
+// Setup test database with test data for statistical aggregations
+func setupStatisticsTestDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db.AutoMigrate(&StatisticsTestData{})
+
+	db.Create(&StatisticsTestData{ID: 1, TrxAmount: 10, TrxType: "income", Quantity: 1})
+	db.Create(&StatisticsTestData{ID: 2, TrxAmount: 20, TrxType: "expense", Quantity: 2})
+	db.Create(&StatisticsTestData{ID: 3, TrxAmount: 30, TrxType: "income", Quantity: 3})
+	db.Create(&StatisticsTestData{ID: 4, TrxAmount: 40, TrxType: "fee", Quantity: 4})
+	db.Create(&StatisticsTestData{ID: 5, TrxAmount: 100, TrxType: "income", Quantity: 5})
+
+	return db
+}
+
+func TestPaginator_StatisticalSummaries(t *testing.T) {
+	db := setupStatisticsTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&StatisticsTestData{}),
+		pagination.WithSummaryFields(
+			"trx_amount:avg",
+			"trx_amount:count",
+			"trx_type:count_distinct",
+			"trx_amount:variance",
+			"trx_amount:stddev",
+			"quantity:variance",
+			"trx_amount:median",
+			"trx_amount:p90",
+		),
+		pagination.WithSummaries(pagination.Percentile("trx_amount", 25).As("q1")),
+	)
+
+	summary, err := paginator.Summary(&[]StatisticsTestData{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, float64(40), summary["trx_amount_avg"])
+	assert.Equal(t, int64(5), summary["trx_amount_count"])
+	assert.Equal(t, int64(3), summary["trx_type_count_distinct"])
+	assert.InDelta(t, 1250, summary["trx_amount_variance"], 1e-9)
+	assert.InDelta(t, math.Sqrt(1250), summary["trx_amount_stddev"], 1e-9)
+	assert.InDelta(t, 2.5, summary["quantity_variance"], 1e-9)
+	assert.Equal(t, float64(30), summary["trx_amount_median"])
+	assert.InDelta(t, 76, summary["trx_amount_p90"], 1e-9)
+	assert.Equal(t, float64(20), summary["q1"])
+}
+
+func TestPaginator_PercentileEmpty(t *testing.T) {
+	db := setupEdgeCaseTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&EdgeCaseTestData{}),
+		pagination.WithSummaries(pagination.Median("trx_amount"), pagination.StdDev("trx_amount")),
+	)
+
+	summary, err := paginator.Summary(&[]EdgeCaseTestData{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, float64(0), summary["trx_amount_median"])
+	assert.Equal(t, float64(0), summary["trx_amount_stddev"])
+}
+
+func TestPaginator_InvalidPercentile(t *testing.T) {
+	db := setupStatisticsTestDB()
+
+	for _, field := range []string{"trx_amount:pNaN", "trx_amount:pInf", "trx_amount:p1e1", "trx_amount:p101", "trx_amount:p"} {
+		paginator := pagination.NewPaginator(db.Model(&StatisticsTestData{}), pagination.WithSummaryFields(field))
+
+		_, err := paginator.Summary(&[]StatisticsTestData{})
+		assert.ErrorIs(t, err, pagination.ErrUnknownAggregation, field)
+	}
+}
+
+func TestPaginator_PercentileSubtotals(t *testing.T) {
+	db := setupStatisticsTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&StatisticsTestData{}),
+		pagination.WithSummaryFields("trx_amount:median:by=trx_type"),
+		pagination.WithSummaries(
+			pagination.Percentile("trx_amount", 90).By("trx_type").
+				Where(pagination.ComparisonFilter{Field: "trx_type", Operator: "!=", Value: "fee"}).As("p90"),
+		),
+	)
+
+	summary, err := paginator.Summary(&[]StatisticsTestData{})
+	assert.Nil(t, err)
+
+	// Interpolated in Go outside of Postgres, per group as well
+	assert.Equal(t, map[string]interface{}{
+		"total": float64(30),
+		"groups": []map[string]interface{}{
+			{"trx_type": "expense", "value": float64(20)},
+			{"trx_type": "fee", "value": float64(40)},
+			{"trx_type": "income", "value": float64(30)},
+		},
+	}, summary["trx_amount_median_by_trx_type"])
+
+	p90 := summary["p90"].(map[string]interface{})
+	assert.InDelta(t, 79, p90["total"], 1e-9)
+	groups := p90["groups"].([]map[string]interface{})
+	assert.Len(t, groups, 2)
+	assert.Equal(t, float64(20), groups[0]["value"])
+	assert.InDelta(t, 86, groups[1]["value"], 1e-9)
+}
@@ -0,0 +1,55 @@
+package pagination_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/xans-me/gorm-pagination/pagination"
+	"testing"
+)
+
+func TestParseSummaryField(t *testing.T) {
+	assert.Equal(t, []pagination.SummarySpec{pagination.Sum("trx_amount")}, pagination.ParseSummaryField("trx_amount"))
+	assert.Equal(t, []pagination.SummarySpec{pagination.Max("trx_amount")}, pagination.ParseSummaryField("trx_amount:max"))
+	assert.Equal(t, []pagination.SummarySpec{pagination.Count("trx_type")}, pagination.ParseSummaryField("trx_type:value_count"))
+	assert.Equal(t, []pagination.SummarySpec{
+		pagination.CountWhere("trx_type", "income"),
+		pagination.CountWhere("trx_type", "fee:atm"),
+	}, pagination.ParseSummaryField("trx_type:value_count:income|fee:atm"))
+
+	// The output key applies to the counts, suffixed by the value when there are several
+	assert.Equal(t, []pagination.SummarySpec{pagination.Count("trx_type").As("types")}, pagination.ParseSummaryField("trx_type:value_count:as=types"))
+	assert.Equal(t, []pagination.SummarySpec{pagination.CountWhere("trx_type", "income").As("incomes")}, pagination.ParseSummaryField("trx_type:value_count:income|as=incomes"))
+	assert.Equal(t, []pagination.SummarySpec{
+		pagination.CountWhere("trx_type", "income").As("trx_income"),
+		pagination.CountWhere("trx_type", "expense").As("trx_expense"),
+	}, pagination.ParseSummaryField("trx_type:value_count:income|expense|as=trx"))
+}
+
+func TestPaginator_WithSummaries(t *testing.T) {
+	db := setupTestDB()
+	db.Create(&TestData{ID: 4, AccountNumber: "123", TrxDate: "2024-04-01", TrxAmount: 5, TrxType: "fee:atm|intl", CIF: "ABC123"})
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithSummaryFields("trx_amount:max"),
+		pagination.WithSummaries(
+			pagination.Sum("trx_amount").As("total"),
+			pagination.Min("trx_amount"),
+			pagination.CountWhere("trx_type", "fee:atm|intl").As("atm_fees"),
+			pagination.CountWhere("trx_type", "income"),
+			pagination.Count("cif"),
+		),
+	)
+
+	var results []TestData
+	res, err := paginator.Paginate(&results)
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"trx_amount_max":        float64(300),
+		"total":                 float64(605),
+		"trx_amount_min":        float64(5),
+		"atm_fees":              int64(1),
+		"trx_type_income_count": int64(2),
+		"cif_count":             int64(4),
+	}, res.Summary)
+}
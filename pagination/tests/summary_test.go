@@ -0,0 +1,211 @@
+package pagination_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/xans-me/gorm-pagination/pagination"
+	"gorm.io/gorm"
+	"testing"
+)
+
+// countQueries counts the queries issued through db, including raw row scans
+func countQueries(db *gorm.DB) *int {
+	queries := 0
+	db.Callback().Query().After("gorm:query").Register("test:count_queries", func(*gorm.DB) { queries++ })
+	db.Callback().Row().After("gorm:row").Register("test:count_rows", func(*gorm.DB) { queries++ })
+	return &queries
+}
+
+func TestPaginator_SummarySingleQuery(t *testing.T) {
+	db := setupTestDB()
+	queries := countQueries(db)
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithSummaryFields(
+			"trx_amount:sum",
+			"trx_amount:min",
+			"trx_amount:max",
+			"trx_type:value_count:income|expense",
+			"cif:value_count",
+			"id:sum",
+			"id:max",
+		),
+	)
+
+	summary, err := paginator.Summary(&[]TestData{})
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, *queries)
+	assert.Equal(t, float64(600), summary["trx_amount_sum"])
+	assert.Equal(t, float64(100), summary["trx_amount_min"])
+	assert.Equal(t, float64(300), summary["trx_amount_max"])
+	assert.Equal(t, int64(2), summary["trx_type_income_count"])
+	assert.Equal(t, int64(1), summary["trx_type_expense_count"])
+	assert.Equal(t, int64(3), summary["cif_count"])
+	assert.Equal(t, float64(6), summary["id_sum"])
+	assert.Equal(t, float64(3), summary["id_max"])
+}
+
+func TestPaginator_SummaryDistribution(t *testing.T) {
+	db := setupTestDB()
+	queries := countQueries(db)
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithSummaryFields("trx_amount:sum", "trx_type:distribution"),
+	)
+
+	summary, err := paginator.Summary(&[]TestData{})
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, *queries)
+
+	distribution, err := json.Marshal(summary["trx_type_distribution"])
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[
+		{"trx_type":"expense","count":1,"percent":33.33},
+		{"trx_type":"income","count":2,"percent":66.67}
+	]`, string(distribution))
+}
+
+func TestPaginator_SummaryDistributionTopN(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithSummaries(pagination.Distribution("account_number").Top(2).RankedBy("trx_amount").As("top_accounts")),
+		pagination.WithSummaryFields("account_number:distribution:limit=1|order=asc"),
+	)
+
+	summary, err := paginator.Summary(&[]TestData{})
+	assert.Nil(t, err)
+
+	assert.Equal(t, []map[string]interface{}{
+		{"account_number": "789", "count": int64(1), "trx_amount_sum": float64(300), "percent": float64(50)},
+		{"account_number": "456", "count": int64(1), "trx_amount_sum": float64(200), "percent": 33.33},
+		{"account_number": nil, "count": int64(1), "trx_amount_sum": float64(100), "percent": 16.67, "other": true},
+	}, summary["top_accounts"])
+
+	// Ties on the count are broken by the value
+	assert.Equal(t, []map[string]interface{}{
+		{"account_number": "123", "count": int64(1), "percent": 33.33},
+		{"account_number": nil, "count": int64(2), "percent": 66.67, "other": true},
+	}, summary["account_number_distribution"])
+}
+
+func TestPaginator_SummarySubtotals(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithSummaryFields("trx_amount:sum:by=trx_type", "trx_amount:sum"),
+		pagination.WithSummaries(pagination.Max("trx_amount").By("trx_type", "account_number").As("max_by_account")),
+	)
+
+	summary, err := paginator.Summary(&[]TestData{})
+	assert.Nil(t, err)
+
+	assert.Equal(t, float64(600), summary["trx_amount_sum"])
+	assert.Equal(t, map[string]interface{}{
+		"total": float64(600),
+		"groups": []map[string]interface{}{
+			{"trx_type": "expense", "value": float64(200)},
+			{"trx_type": "income", "value": float64(400)},
+		},
+	}, summary["trx_amount_sum_by_trx_type"])
+
+	assert.Equal(t, map[string]interface{}{
+		"total": float64(300),
+		"groups": []map[string]interface{}{
+			{"trx_type": "expense", "value": float64(200), "groups": []map[string]interface{}{
+				{"account_number": "456", "value": float64(200)},
+			}},
+			{"trx_type": "income", "value": float64(300), "groups": []map[string]interface{}{
+				{"account_number": "123", "value": float64(100)},
+				{"account_number": "789", "value": float64(300)},
+			}},
+		},
+	}, summary["max_by_account"])
+}
+
+func TestPaginator_SummarySubtotalsRejectBuckets(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithSummaries(pagination.Distribution("cif").By("trx_type")),
+	)
+
+	_, err := paginator.Summary(&[]TestData{})
+	assert.ErrorIs(t, err, pagination.ErrInvalidSummary)
+}
+
+func TestPaginator_SummaryEmptyTable(t *testing.T) {
+	db := setupEdgeCaseTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&EdgeCaseTestData{}),
+		pagination.WithSummaryFields("trx_amount:sum", "trx_type:value_count:income", "trx_type:distribution", "account_number:distribution:limit=2"),
+	)
+
+	summary, err := paginator.Summary(&[]EdgeCaseTestData{})
+	assert.Nil(t, err)
+
+	assert.Equal(t, float64(0), summary["trx_amount_sum"])
+	assert.Equal(t, int64(0), summary["trx_type_income_count"])
+
+	// Distributions over no rows are empty lists rather than null
+	data, err := json.Marshal(summary)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"trx_amount_sum":0,"trx_type_income_count":0,"trx_type_distribution":[],"account_number_distribution":[]}`, string(data))
+}
+
+func TestPaginator_SummaryErrorsFail(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithSummaryFields("trx_amount:summ"),
+	)
+
+	var results []TestData
+	_, err := paginator.Paginate(&results)
+	assert.ErrorIs(t, err, pagination.ErrUnknownAggregation)
+
+	paginator = pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithSummaryFields("trx_amount:sum", "trx_amout:sum"),
+	)
+
+	_, err = paginator.Paginate(&results)
+	assert.ErrorContains(t, err, "trx_amout")
+}
+
+func TestPaginator_SummaryErrorsReport(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&TestData{}),
+		pagination.WithSummaryFields("trx_amount:sum", "trx_amout:sum", "trx_amount:summ", "trx_typ:distribution"),
+		pagination.WithSummaryErrorPolicy(pagination.SummaryErrorsReport),
+	)
+
+	var results []TestData
+	res, err := paginator.Paginate(&results)
+
+	assert.Nil(t, err)
+	assert.Equal(t, float64(600), res.Summary["trx_amount_sum"])
+	assert.Contains(t, res.SummaryErrors, "trx_amout_sum")
+	assert.Contains(t, res.SummaryErrors, "trx_amount_summ")
+	assert.Contains(t, res.SummaryErrors, "trx_typ_distribution")
+	assert.Len(t, res.SummaryErrors, 3)
+
+	summary, err := paginator.Summary(&results)
+
+	var summaryErr *pagination.SummaryError
+	assert.ErrorAs(t, err, &summaryErr)
+	assert.ErrorIs(t, err, pagination.ErrUnknownAggregation)
+	assert.Len(t, summaryErr.Errors, 3)
+	assert.Equal(t, float64(600), summary["trx_amount_sum"])
+}
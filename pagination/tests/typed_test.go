@@ -0,0 +1,48 @@
+package pagination_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/xans-me/gorm-pagination/pagination"
+	"testing"
+)
+
+func TestPaginate_Typed(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(
+		db,
+		pagination.WithPageSize(2),
+		pagination.WithSort("trx_amount desc"),
+		pagination.WithSummaryFields("trx_amount:sum"),
+	)
+
+	res, err := pagination.Paginate[TestData](paginator)
+
+	assert.Nil(t, err)
+	assert.Len(t, res.Data, 2)
+	assert.Equal(t, 300.0, res.Data[0].TrxAmount)
+	assert.Equal(t, int64(3), res.TotalData)
+	assert.Equal(t, 2, res.TotalPages)
+	assert.Equal(t, float64(600), res.Summary["trx_amount_sum"])
+}
+
+func TestPaginate_TypedJSON(t *testing.T) {
+	db := setupTestDB()
+
+	paginator := pagination.NewPaginator(db, pagination.WithPageSize(1))
+
+	res, err := pagination.Paginate[TestData](paginator)
+	assert.Nil(t, err)
+
+	body, err := json.Marshal(res)
+	assert.Nil(t, err)
+
+	var decoded struct {
+		Data      []TestData `json:"data"`
+		TotalData int64      `json:"totalData"`
+	}
+	assert.Nil(t, json.Unmarshal(body, &decoded))
+	assert.Len(t, decoded.Data, 1)
+	assert.Equal(t, int64(3), decoded.TotalData)
+}
@@ -0,0 +1,175 @@
+package pagination_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/xans-me/gorm-pagination/pagination"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestPaginator_ModelFields(t *testing.T) {
+	db := setupSortTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&SortTestData{}),
+		pagination.WithModelFields(),
+		pagination.WithSort("trx_amount desc"),
+		pagination.WithFilters(pagination.Or(
+			pagination.ComparisonFilter{Field: "account_number", Operator: "=", Value: "123"},
+			pagination.SearchFilter{Field: "trx_date", Value: "01-03"},
+		)),
+	)
+
+	var results []SortTestData
+	res, err := paginator.Paginate(&results)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), res.TotalData)
+	assert.Equal(t, 300.0, results[0].TrxAmount)
+
+	paginator = pagination.NewPaginator(
+		db.Model(&SortTestData{}),
+		pagination.WithModelFields(),
+		pagination.WithSort("password desc"),
+	)
+
+	_, err = paginator.Paginate(&results)
+	var validationErr *pagination.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "field", validationErr.Kind)
+	assert.Equal(t, "password", validationErr.Value)
+}
+
+func TestPaginator_AllowedFields(t *testing.T) {
+	db := setupSortTestDB()
+
+	paginator := pagination.NewPaginator(
+		db.Model(&SortTestData{}),
+		pagination.WithAllowedFields("trx_amount"),
+		pagination.WithFilters(pagination.Not(pagination.ComparisonFilter{Field: "account_number", Operator: "=", Value: "123"})),
+	)
+
+	var results []SortTestData
+	_, err := paginator.Paginate(&results)
+	assert.EqualError(t, err, `invalid field "account_number": not an allowed field`)
+
+	_, err = paginator.Summary(&results)
+	var validationErr *pagination.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestPaginator_InvalidOperatorAndDirection(t *testing.T) {
+	db := setupSortTestDB()
+
+	paginator := pagination.NewPaginator(db.Model(&SortTestData{}), pagination.WithSort("trx_amount sideways"))
+
+	var results []SortTestData
+	_, err := paginator.Paginate(&results)
+	assert.EqualError(t, err, `invalid direction "sideways": must be "asc" or "desc"`)
+
+	// Trailing tokens are rejected rather than dropped
+	paginator = pagination.NewPaginator(db.Model(&SortTestData{}), pagination.WithSort("id, trx_amount desc nulls first"))
+	_, err = paginator.Paginate(&results)
+	assert.EqualError(t, err, `invalid sort "trx_amount desc nulls first": must be a field optionally followed by "asc" or "desc"`)
+
+	paginator = pagination.NewPaginator(db.Model(&SortTestData{}), pagination.WithSort("trx_amount desc nulls first"), pagination.WithCursor(""))
+	_, err = paginator.Paginate(&results)
+	assert.ErrorAs(t, err, new(*pagination.ValidationError))
+
+	paginator = pagination.NewPaginator(
+		db.Model(&SortTestData{}),
+		pagination.WithFilters(pagination.ComparisonFilter{Field: "id", Operator: "= 1 OR 1 =", Value: 1}),
+	)
+
+	_, err = paginator.Paginate(&results)
+	var validationErr *pagination.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "operator", validationErr.Kind)
+}
+
+func TestPaginator_QuotedIdentifiers(t *testing.T) {
+	db := setupSortTestDB()
+
+	sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		query := pagination.SearchFilter{Field: "sort_test_data.account_number", Value: "1"}.Apply(tx.Model(&SortTestData{}))
+		return pagination.OrderBy{Field: "trx_date", Direction: "desc"}.Apply(query).Find(&[]SortTestData{})
+	})
+	assert.Equal(t, "SELECT * FROM `sort_test_data` WHERE `sort_test_data`.`account_number` LIKE \"%1%\" ESCAPE '!' ORDER BY `trx_date` DESC", sql)
+}
+
+func TestPaginator_AllowedSummaryFields(t *testing.T) {
+	db := setupSortTestDB()
+
+	tests := []struct {
+		name   string
+		option pagination.PaginatorOption
+		field  string
+	}{
+		{"field", pagination.WithSummaryFields("password:sum"), "password"},
+		{"subtotal", pagination.WithSummaries(pagination.Sum("trx_amount").By("password")), "password"},
+		{"rank", pagination.WithSummaries(pagination.Distribution("account_number").RankedBy("password")), "password"},
+		{"sum", pagination.WithSummaries(pagination.Histogram("trx_amount", 2).WithSum("password")), "password"},
+		{"condition", pagination.WithSummaries(pagination.Sum("trx_amount").Where(pagination.NullFilter{Field: "password"})), "password"},
+		{"grouping", pagination.WithGroupBy("password"), "password"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paginator := pagination.NewPaginator(db.Model(&SortTestData{}), pagination.WithModelFields(), tt.option)
+
+			_, err := paginator.Summary(&[]SortTestData{})
+			var validationErr *pagination.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.field, validationErr.Value)
+		})
+	}
+}
+
+type KeywordTestData struct {
+	ID    int
+	Group string
+	Order float64
+	Date  string
+}
+
+func TestPaginator_QuotedSummaryFields(t *testing.T) {
+	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db.AutoMigrate(&KeywordTestData{})
+	db.Create(&KeywordTestData{ID: 1, Group: "a", Order: 10, Date: "2024-01-01"})
+	db.Create(&KeywordTestData{ID: 2, Group: "b", Order: 20, Date: "2024-01-01"})
+	db.Create(&KeywordTestData{ID: 3, Group: "a", Order: 30, Date: "2024-01-02"})
+
+	income := pagination.ComparisonFilter{Field: "group", Operator: "=", Value: "a"}
+	paginator := pagination.NewPaginator(
+		db.Model(&KeywordTestData{}),
+		pagination.WithModelFields(),
+		pagination.WithGroupBy("group"),
+		pagination.WithSummaries(
+			pagination.Sum("order").By("group"),
+			pagination.Sum("order").Where(income).As("a_sum"),
+			pagination.Variance("order"),
+			pagination.Median("order"),
+			pagination.CountWhere("group", "a"),
+			pagination.Net("order", income, pagination.Not(income)),
+			pagination.Distribution("group").RankedBy("order"),
+			pagination.Histogram("order", 2).WithSum("order"),
+			pagination.DateHistogram("date", "day").WithSum("order"),
+		),
+	)
+
+	summary, err := paginator.Summary(&[]KeywordTestData{})
+	assert.Nil(t, err)
+	assert.Equal(t, float64(60), summary["order_sum_by_group"].(map[string]interface{})["total"])
+	assert.Equal(t, float64(40), summary["a_sum"])
+	assert.Equal(t, float64(100), summary["order_variance"])
+	assert.Equal(t, float64(20), summary["order_median"])
+	assert.Equal(t, int64(2), summary["group_a_count"])
+	assert.Equal(t, float64(20), summary["order_net"])
+	assert.Equal(t, float64(40), summary["group_distribution"].([]map[string]interface{})[0]["order_sum"])
+	assert.Len(t, summary["order_histogram"], 2)
+	assert.Len(t, summary["date_date_histogram"], 2)
+
+	var results []KeywordTestData
+	res, err := paginator.Paginate(&results)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), res.TotalData)
+}
@@ -0,0 +1,29 @@
+package pagination
+
+import "context"
+
+// TypedResult contains the paginated result with strongly typed data.
+type TypedResult[T any] struct {
+	Result
+	Data []T `json:"data"`
+}
+
+// Paginate executes the pagination for model T. The table used for the count and
+// summary queries is inferred from T, so p.DB does not need a Model.
+func Paginate[T any](p *Paginator) (*TypedResult[T], error) {
+	return PaginateContext[T](context.Background(), p)
+}
+
+// PaginateContext executes the pagination for model T, running the queries with ctx.
+func PaginateContext[T any](ctx context.Context, p *Paginator) (*TypedResult[T], error) {
+	var data []T
+	res, err := p.PaginateContext(ctx, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TypedResult[T]{
+		Result: *res,
+		Data:   data,
+	}, nil
+}
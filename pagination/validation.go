@@ -0,0 +1,161 @@
+package pagination
+
+import (
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ValidationError reports a field, operator or sort direction that the paginator rejects
+// before building any SQL.
+type ValidationError struct {
+	Kind   string // "field", "operator", "direction", "sort" or "value"
+	Value  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid " + e.Kind + " " + strconv.Quote(e.Value) + ": " + e.Reason
+}
+
+// validOperator reports whether operator is one of the comparison operators a
+// ComparisonFilter accepts.
+func validOperator(operator string) bool {
+	switch operator {
+	case "=", "!=", "<>", ">", ">=", "<", "<=":
+		return true
+	}
+	return false
+}
+
+// checkOperator returns a ValidationError unless operator is a valid comparison operator.
+func checkOperator(operator string) error {
+	if !validOperator(operator) {
+		return &ValidationError{Kind: "operator", Value: operator, Reason: "not a comparison operator"}
+	}
+	return nil
+}
+
+// checkDirection returns a ValidationError unless direction is "asc", "desc" or empty.
+func checkDirection(direction string) error {
+	switch strings.ToLower(direction) {
+	case "", "asc", "desc":
+		return nil
+	}
+	return &ValidationError{Kind: "direction", Value: direction, Reason: `must be "asc" or "desc"`}
+}
+
+// fieldFilter is implemented by the built-in filters to report the fields they reference.
+type fieldFilter interface {
+	fields() []string
+}
+
+// filterFields returns the fields referenced by filter and, for filter groups, by their
+// children. Fields of user-defined filters are not known and not reported.
+func filterFields(filter Filter) []string {
+	if filter, ok := filter.(fieldFilter); ok {
+		return filter.fields()
+	}
+	return nil
+}
+
+// groupFields returns the fields referenced by filters.
+func groupFields(filters []Filter) []string {
+	var fields []string
+	for _, filter := range filters {
+		fields = append(fields, filterFields(filter)...)
+	}
+	return fields
+}
+
+// summaryFields returns the fields aggregated, ranked, summed, subtotalled or filtered on by
+// spec.
+func summaryFields(spec SummarySpec) []string {
+	fields := []string{spec.Field}
+	if spec.RankBy != "" {
+		fields = append(fields, spec.RankBy)
+	}
+	fields = append(fields, spec.SumFields...)
+	fields = append(fields, spec.GroupBy...)
+	fields = append(fields, groupFields(spec.Conditions)...)
+	fields = append(fields, groupFields(spec.Inflow)...)
+	return append(fields, groupFields(spec.Outflow)...)
+}
+
+// validate checks the sort directions and, when StrictFields is set, the filter, sort,
+// grouping and summary fields of the paginator against AllowedFields or, without any, the
+// columns of model. Summary fields that fail to parse are left to the summary to report.
+func (p *Paginator) validate(model interface{}) error {
+	var orders []OrderBy
+	for _, ordering := range p.Orderings {
+		switch o := ordering.(type) {
+		case OrderBy:
+			orders = append(orders, o)
+		case *OrderBy:
+			orders = append(orders, *o)
+		}
+	}
+	for _, sort := range p.Sort {
+		sorted, err := parseSort(sort)
+		if err != nil {
+			return err
+		}
+		orders = append(orders, sorted...)
+	}
+
+	for _, order := range orders {
+		if err := checkDirection(order.Direction); err != nil {
+			return err
+		}
+	}
+	if !p.StrictFields {
+		return nil
+	}
+
+	allowed, err := p.allowedFields(model)
+	if err != nil {
+		return err
+	}
+
+	fields := groupFields(p.Filters)
+	for _, order := range orders {
+		fields = append(fields, order.Field)
+	}
+	fields = append(fields, p.Groups...)
+	specs := p.Summaries
+	for _, field := range p.SummaryFields {
+		specs = append(specs, ParseSummaryField(field)...)
+	}
+	for _, spec := range specs {
+		if spec.err == nil {
+			fields = append(fields, summaryFields(spec)...)
+		}
+	}
+	for _, field := range fields {
+		if !allowed[field] {
+			return &ValidationError{Kind: "field", Value: field, Reason: "not an allowed field"}
+		}
+	}
+	return nil
+}
+
+// allowedFields returns AllowedFields as a set or, when empty, the column names of model.
+func (p *Paginator) allowedFields(model interface{}) (map[string]bool, error) {
+	allowed := make(map[string]bool)
+	if len(p.AllowedFields) > 0 {
+		for _, field := range p.AllowedFields {
+			allowed[field] = true
+		}
+		return allowed, nil
+	}
+
+	stmt := &gorm.Statement{DB: p.DB}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	for _, column := range stmt.Schema.DBNames {
+		allowed[column] = true
+	}
+	return allowed, nil
+}